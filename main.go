@@ -1,15 +1,32 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
+	"unicode"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -17,12 +34,27 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 const (
 	configFileName = ".todobi.conf"
 	minWidth       = 40
 	minHeight      = 10
+	tickInterval   = time.Minute
+	syncTimeout    = 30 * time.Second
+)
+
+// Adaptive palette for UI chrome, so text stays legible on light and dark
+// terminal backgrounds. Priority and category colors read fine on both.
+var (
+	colorText    = lipgloss.AdaptiveColor{Light: "#1e1e1e", Dark: "#d4d4d4"}
+	colorSubtle  = lipgloss.AdaptiveColor{Light: "#555555", Dark: "#999"}
+	colorMuted   = lipgloss.AdaptiveColor{Light: "#767676", Dark: "#666"}
+	colorFaint   = lipgloss.AdaptiveColor{Light: "#c6c6c6", Dark: "#333"}
+	colorBar     = lipgloss.AdaptiveColor{Light: "#e4e4e4", Dark: "#333333"}
+	colorAccent  = lipgloss.AdaptiveColor{Light: "#00796b", Dark: "#4ec9b0"}
+	colorWarning = lipgloss.AdaptiveColor{Light: "#9a6700", Dark: "#ffc107"}
 )
 
 // Priority levels
@@ -50,6 +82,20 @@ func (p Priority) String() string {
 	}
 }
 
+// Label is the long name shown in group headers
+func (p Priority) Label() string {
+	switch p {
+	case P0Critical:
+		return "Critical"
+	case P2Medium:
+		return "Medium"
+	case P3Low:
+		return "Low"
+	default:
+		return "High"
+	}
+}
+
 func (p Priority) Color() string {
 	switch p {
 	case P0Critical:
@@ -65,22 +111,220 @@ func (p Priority) Color() string {
 	}
 }
 
+// TaskState tracks where a task is in its lifecycle
+type TaskState string
+
+const (
+	StateTodo    TaskState = "todo"
+	StateDoing   TaskState = "doing"
+	StateWaiting TaskState = "waiting"
+	StateDone    TaskState = "done"
+)
+
+// next returns the state after s when cycling with the s key
+func (s TaskState) next() TaskState {
+	switch s {
+	case StateTodo:
+		return StateDoing
+	case StateDoing:
+		return StateWaiting
+	case StateWaiting:
+		return StateDone
+	default:
+		return StateTodo
+	}
+}
+
+// checkbox renders the state marker shown before a task
+func (s TaskState) checkbox() string {
+	switch s {
+	case StateDoing:
+		return lipgloss.NewStyle().Foreground(colorAccent).Render("[>]")
+	case StateWaiting:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#c586c0")).Render("[w]")
+	case StateDone:
+		return "[x]"
+	default:
+		return "[ ]"
+	}
+}
+
 // Task represents a todo item
 type Task struct {
-	ID          string    `json:"id"`
-	Content     string    `json:"content"`
-	CategoryID  string    `json:"category_id"`
-	Priority    Priority  `json:"priority"`
-	Done        bool      `json:"done"`
-	CreatedAt   time.Time `json:"created_at"`
-	CompletedAt time.Time `json:"completed_at,omitempty"`
-	Notes       string    `json:"notes,omitempty"`
+	ID             string      `json:"id"`
+	Content        string      `json:"content"`
+	CategoryID     string      `json:"category_id"`
+	Priority       Priority    `json:"priority"`
+	Done           bool        `json:"done"`
+	CreatedAt      time.Time   `json:"created_at"`
+	CompletedAt    time.Time   `json:"completed_at,omitempty"`
+	Notes          string      `json:"notes,omitempty"`
+	URLs           []string    `json:"urls,omitempty"`
+	URL            string      `json:"url,omitempty"` // pre-URLs single link, moved into URLs on load
+	SpentMinutes   int         `json:"spent_minutes,omitempty"`
+	SnoozedUntil   time.Time   `json:"snoozed_until,omitempty"`
+	Backlog        bool        `json:"backlog,omitempty"` // someday/maybe, kept off the active list
+	State          TaskState   `json:"state,omitempty"`
+	Rank           int         `json:"rank,omitempty"` // manual order within a priority
+	TimesCompleted int         `json:"times_completed,omitempty"`
+	Events         []TaskEvent `json:"events,omitempty"`
+
+	unknown unknownFields // fields this build doesn't know, written back as is
+}
+
+func (t Task) MarshalJSON() ([]byte, error) {
+	type plain Task
+	data, err := json.Marshal(plain(t))
+	if err != nil {
+		return nil, err
+	}
+	return t.unknown.appendTo(data), nil
+}
+
+// maxTaskEvents bounds each task's history so the config doesn't grow forever
+const maxTaskEvents = 20
+
+// TaskEvent records one change in a task's history
+type TaskEvent struct {
+	At     time.Time `json:"at"`
+	Type   string    `json:"type"`
+	Detail string    `json:"detail,omitempty"`
+
+	unknown unknownFields // fields this build doesn't know, written back as is
+}
+
+func (e TaskEvent) MarshalJSON() ([]byte, error) {
+	type plain TaskEvent
+	data, err := json.Marshal(plain(e))
+	if err != nil {
+		return nil, err
+	}
+	return e.unknown.appendTo(data), nil
+}
+
+// logEvent appends to the task's history, dropping the oldest entries
+// past maxTaskEvents
+func (t *Task) logEvent(kind, detail string, now time.Time) {
+	t.Events = append(t.Events, TaskEvent{At: now, Type: kind, Detail: detail})
+	if len(t.Events) > maxTaskEvents {
+		t.Events = t.Events[len(t.Events)-maxTaskEvents:]
+	}
+}
+
+// setNotes replaces the task's notes and reports whether they changed
+func (t *Task) setNotes(notes string, now time.Time) bool {
+	if t.Notes == notes {
+		return false
+	}
+	t.Notes = notes
+	t.logEvent("notes", "", now)
+	return true
+}
+
+// state returns the task's state; Done always wins so older configs and
+// clients that only know the boolean stay consistent
+func (t Task) state() TaskState {
+	if t.Done {
+		return StateDone
+	}
+	if t.State == "" || t.State == StateDone {
+		return StateTodo
+	}
+	return t.State
+}
+
+// setState moves the task to s, keeping Done and CompletedAt in step.
+// TimesCompleted counts every completion, so reopening doesn't lose them.
+func (t *Task) setState(s TaskState, now time.Time) {
+	prev := t.state()
+	wasDone := t.Done
+	t.State = s
+	t.Done = s == StateDone
+	if t.Done && !wasDone {
+		t.CompletedAt = now
+		t.TimesCompleted++
+	} else if !t.Done {
+		t.CompletedAt = time.Time{}
+	}
+
+	switch {
+	case s == prev:
+	case t.Done:
+		t.logEvent("completed", "", now)
+	case wasDone:
+		t.logEvent("reopened", "to "+string(s), now)
+	default:
+		t.logEvent("state", string(prev)+" → "+string(s), now)
+	}
+}
+
+// lastActivity is when the task was last touched: its newest history
+// entry (edits, moves, notes, state changes), or its creation
+func (t Task) lastActivity() time.Time {
+	last := t.CreatedAt
+	for _, event := range t.Events {
+		if event.At.After(last) {
+			last = event.At
+		}
+	}
+	return last
+}
+
+// firstURL is the task's first link, or "" when it has none
+func (t Task) firstURL() string {
+	if len(t.URLs) == 0 {
+		return ""
+	}
+	return t.URLs[0]
+}
+
+// setURLs replaces the task's links, logging the change
+func (t *Task) setURLs(urls []string, now time.Time) {
+	if slices.Equal(t.URLs, urls) {
+		return
+	}
+	t.URLs = urls
+	t.logEvent("url", strings.Join(urls, " "), now)
+}
+
+// parseURLs splits the form's link field on whitespace; commas are left
+// alone since they're valid inside a URL
+func parseURLs(s string) []string {
+	return cleanURLs(strings.Fields(s))
+}
+
+// cleanURLs trims links, dropping empty ones and repeats
+func cleanURLs(links []string) []string {
+	var urls []string
+	for _, u := range links {
+		if u = strings.TrimSpace(u); u != "" && !slices.Contains(urls, u) {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// isSnoozed reports whether a pending task is hidden until a later time
+func (t Task) isSnoozed(now time.Time) bool {
+	return !t.Done && t.SnoozedUntil.After(now)
+}
+
+// inBacklog reports whether a pending task is parked in the someday/maybe
+// backlog; finishing it takes it out, reopening puts it back
+func (t Task) inBacklog() bool {
+	return !t.Done && t.Backlog
 }
 
 // TaskItem wraps Task with category name for display
 type TaskItem struct {
 	Task
-	CategoryName string
+	CategoryName  string
+	CategoryColor string
+	Times         timeStyle
+	Hyperlinks    bool
+	Stale         bool   // pending longer than stale_days
+	StaleFirst    bool   // stale_first: sort stale tasks to the top of their category
+	Highlight     string // search query to mark in the content
 }
 
 // Implement list.Item interface for TaskItem
@@ -90,12 +334,13 @@ func (t TaskItem) Title() string {
 		Bold(true)
 
 	categoryStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666")).
+		Foreground(categoryColor(t.CategoryColor)).
 		Italic(true)
 
-	checkbox := "[ ]"
-	if t.Done {
-		checkbox = "[x]"
+	checkbox := t.state().checkbox()
+	content := highlightMatches(t.Content, t.Highlight, lipgloss.NewStyle()) + t.linkMarker()
+	if t.Stale {
+		content += " ⏳"
 	}
 
 	// Show category name for completed tasks
@@ -103,7 +348,7 @@ func (t TaskItem) Title() string {
 		return fmt.Sprintf("%s %-4s %s %s",
 			checkbox,
 			priorityStyle.Render(t.Priority.String()),
-			t.Content,
+			content,
 			categoryStyle.Render("["+t.CategoryName+"]"),
 		)
 	}
@@ -111,25 +356,227 @@ func (t TaskItem) Title() string {
 	return fmt.Sprintf("%s %-4s %s",
 		checkbox,
 		priorityStyle.Render(t.Priority.String()),
-		t.Content,
+		content,
 	)
 }
 
+// searchMatchStyle marks the text a search query matched
+var searchMatchStyle = lipgloss.NewStyle().Reverse(true)
+
+// highlightMatches styles every case-insensitive occurrence of query in s
+// with searchMatchStyle and the rest with base
+func highlightMatches(s, query string, base lipgloss.Style) string {
+	if query == "" {
+		return base.Render(s)
+	}
+	lower := func(text string) []rune {
+		runes := []rune(text)
+		for i, r := range runes {
+			runes[i] = unicode.ToLower(r)
+		}
+		return runes
+	}
+	text, needle := lower(s), lower(query)
+
+	var matched []int
+	for i := 0; i+len(needle) <= len(text); {
+		if string(text[i:i+len(needle)]) != string(needle) {
+			i++
+			continue
+		}
+		for j := i; j < i+len(needle); j++ {
+			matched = append(matched, j)
+		}
+		i += len(needle)
+	}
+	if len(matched) == 0 {
+		return base.Render(s)
+	}
+	return lipgloss.StyleRunes(s, matched, searchMatchStyle, base)
+}
+
+// linkMarker returns a " ↗" suffix for tasks with a URL, clickable in
+// terminals that support hyperlinks; several links add a count (" ↗3")
+func (t TaskItem) linkMarker() string {
+	if len(t.URLs) == 0 {
+		return ""
+	}
+	marker := "↗"
+	if t.Hyperlinks {
+		marker = hyperlink(marker, t.URLs[0])
+	}
+	if len(t.URLs) > 1 {
+		marker += strconv.Itoa(len(t.URLs))
+	}
+	return " " + marker
+}
+
+// hyperlink wraps text in an OSC 8 escape so capable terminals open url on click
+func hyperlink(text, url string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// terminalSupportsHyperlinks guesses OSC 8 support from the environment
+func terminalSupportsHyperlinks() bool {
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("WT_SESSION") != "" {
+		return true
+	}
+	term := os.Getenv("TERM")
+	if strings.Contains(term, "kitty") || strings.Contains(term, "ghostty") || strings.Contains(term, "wezterm") {
+		return true
+	}
+	// GNOME Terminal and other VTE terminals since 0.50
+	var vte int
+	fmt.Sscanf(os.Getenv("VTE_VERSION"), "%d", &vte)
+	return vte >= 5000
+}
+
+// cycleTime is how long a completed task took from creation to done;
+// ok is false for pending tasks and ones missing either timestamp
+func (t Task) cycleTime() (d time.Duration, ok bool) {
+	if !t.Done || t.CreatedAt.IsZero() || t.CompletedAt.IsZero() {
+		return 0, false
+	}
+	d = t.CompletedAt.Sub(t.CreatedAt)
+	if d < 0 {
+		// Clock skew between synced machines
+		d = 0
+	}
+	return d, true
+}
+
+// formatCycleTime renders a cycle time as "done in 3 days"; tasks finished
+// within a minute of being added read "done right away"
+func formatCycleTime(d time.Duration) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return "done in 1 " + unit
+		}
+		return fmt.Sprintf("done in %d %ss", n, unit)
+	}
+	switch {
+	case d < time.Minute:
+		return "done right away"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour")
+	}
+	return plural(int(d.Hours()/24), "day")
+}
+
+// timeStyle carries the date settings to rendering code that has no config
+type timeStyle struct {
+	layout   string // date_format
+	relative *bool  // relative_dates; nil keeps ages relative and timestamps absolute
+}
+
+func (c *Config) timeStyle() timeStyle {
+	return timeStyle{layout: c.dateFormat(), relative: c.RelativeDates}
+}
+
+// relativeOn reports whether timestamps render relative ("2 days ago")
+func (s timeStyle) relativeOn() bool {
+	return s.relative != nil && *s.relative
+}
+
+// formatTime renders a timestamp as a date and time, or relative to now
+// ("2 days ago", "in 3 hours") with relative_dates on
+func (s timeStyle) formatTime(t, now time.Time) string {
+	if !s.relativeOn() {
+		return t.Format(s.layout + " 15:04")
+	}
+	return relativeTime(t, now)
+}
+
+// relativeTime renders t as "2 days ago" or "in 3 hours" from now
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	plural := func(n int, unit string) string {
+		text := fmt.Sprintf("%d %ss", n, unit)
+		if n == 1 {
+			text = "1 " + unit
+		}
+		if future {
+			return "in " + text
+		}
+		return text + " ago"
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour")
+	}
+	return plural(int(d.Hours()/24), "day")
+}
+
+// humanizeAge describes how old a task is ("3 days old"), or gives its
+// creation time when relative_dates is off
+func (s timeStyle) humanizeAge(created, now time.Time) string {
+	if s.relative != nil && !*s.relative {
+		return "Created " + created.Format(s.layout+" 15:04")
+	}
+	switch days := int(now.Sub(created).Hours() / 24); days {
+	case 0:
+		return "Created today"
+	case 1:
+		return "1 day old"
+	default:
+		return fmt.Sprintf("%d days old", days)
+	}
+}
+
+// snoozeText says when a snoozed task comes back
+func (s timeStyle) snoozeText(until, now time.Time) string {
+	if s.relativeOn() {
+		return "snoozed, back " + s.formatTime(until, now)
+	}
+	return "snoozed until " + s.formatTime(until, now)
+}
+
 func (t TaskItem) Description() string {
-	age := time.Since(t.CreatedAt)
-	days := int(age.Hours() / 24)
-
-	var ageStr string
-	if days == 0 {
-		ageStr = "Created today"
-	} else if days == 1 {
-		ageStr = "1 day old"
-	} else {
-		ageStr = fmt.Sprintf("%d days old", days)
+	now := time.Now()
+	ageStr := t.Times.humanizeAge(t.CreatedAt, now)
+	if t.Stale {
+		ageStr = "⏳ stale • " + ageStr
 	}
 
 	if t.Done {
-		return fmt.Sprintf("Completed: %s • %s", t.CompletedAt.Format("2006-01-02 15:04"), ageStr)
+		// Time since completion, then how long it took; never the age
+		if t.CompletedAt.IsZero() {
+			return "Completed"
+		}
+		completed := "Completed: " + t.Times.formatTime(t.CompletedAt, now)
+		if t.Times.relative == nil {
+			completed += " (" + relativeTime(t.CompletedAt, now) + ")"
+		}
+		if d, ok := t.cycleTime(); ok {
+			completed += " • " + formatCycleTime(d)
+		}
+		return completed
+	}
+	if t.inBacklog() {
+		return "Someday/maybe • " + ageStr
+	}
+	if t.isSnoozed(now) {
+		return fmt.Sprintf("💤 %s • %s", t.Times.snoozeText(t.SnoozedUntil, now), ageStr)
+	}
+	switch t.state() {
+	case StateDoing:
+		return "In progress • " + ageStr
+	case StateWaiting:
+		return "Waiting on someone else • " + ageStr
 	}
 	return ageStr
 }
@@ -138,12 +585,71 @@ func (t TaskItem) FilterValue() string {
 	return t.Content
 }
 
+// columnDelegate renders tasks as one aligned row each: checkbox,
+// priority, content, category, and created date
+type columnDelegate struct{}
+
+func (d columnDelegate) Height() int                             { return 1 }
+func (d columnDelegate) Spacing() int                            { return 0 }
+func (d columnDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+
+func (d columnDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	t, ok := item.(TaskItem)
+	if !ok {
+		return
+	}
+
+	const (
+		categoryWidth = 14
+		dateWidth     = 10
+	)
+	// cursor(2) + checkbox(3) + priority(2) + spaces between columns(4)
+	contentWidth := max(m.Width()-categoryWidth-dateWidth-11, 10)
+
+	pad := func(s string, width int) string {
+		s = ansi.Truncate(s, width, "…")
+		return s + strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
+	}
+
+	checkbox := t.state().checkbox()
+
+	priorityStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(t.Priority.Color())).
+		Bold(true)
+	rowStyle := lipgloss.NewStyle().Foreground(colorText)
+	dateStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	if t.Stale {
+		dateStyle = lipgloss.NewStyle().Foreground(colorWarning)
+	}
+
+	cursor := "  "
+	if index == m.Index() {
+		cursor = "> "
+		rowStyle = rowStyle.Foreground(colorAccent).Bold(true)
+	}
+
+	fmt.Fprintf(w, "%s%s %s %s %s %s",
+		cursor,
+		checkbox,
+		priorityStyle.Render(t.Priority.String()),
+		highlightMatches(pad(t.Content, contentWidth-lipgloss.Width(t.linkMarker())), t.Highlight, rowStyle)+t.linkMarker(),
+		lipgloss.NewStyle().
+			Foreground(categoryColor(t.CategoryColor)).
+			Render(pad(t.CategoryName, categoryWidth)),
+		dateStyle.Render(t.CreatedAt.Format(t.Times.layout)),
+	)
+}
+
 // Implement list.Item interface for Category
 func (c Category) Title() string {
 	return c.Name
 }
 
 func (c Category) Description() string {
+	if c.Notes != "" {
+		first, _, _ := strings.Cut(c.Notes, "\n")
+		return fmt.Sprintf("ID: %s • 📝 %s", c.ID, first)
+	}
 	return fmt.Sprintf("ID: %s", c.ID)
 }
 
@@ -151,10 +657,60 @@ func (c Category) FilterValue() string {
 	return c.Name
 }
 
+// renameDelegate draws one category row with the inline rename input in
+// place of its name; every other row renders as usual
+type renameDelegate struct {
+	list.DefaultDelegate
+	index int
+	input string
+}
+
+func (d renameDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if cat, ok := item.(Category); ok && index == d.index {
+		cat.Name = d.input
+		item = cat
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
 // Category for organizing tasks
 type Category struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+	Notes string `json:"notes,omitempty"` // project-level scratchpad
+
+	unknown unknownFields // fields this build doesn't know, written back as is
+}
+
+func (c Category) MarshalJSON() ([]byte, error) {
+	type plain Category
+	data, err := json.Marshal(plain(c))
+	if err != nil {
+		return nil, err
+	}
+	return c.unknown.appendTo(data), nil
+}
+
+// categoryPalette lists the colors offered in the category form; the
+// empty entry keeps the default grey
+var categoryPalette = []string{
+	"",
+	"#d73a4a",
+	"#fb8500",
+	"#ffc107",
+	"#4caf50",
+	"#4ec9b0",
+	"#569cd6",
+	"#c586c0",
+}
+
+// categoryColor returns the display color for a category, falling back to grey
+func categoryColor(color string) lipgloss.TerminalColor {
+	if color == "" {
+		return colorMuted
+	}
+	return lipgloss.Color(color)
 }
 
 // Config stores all tasks and categories
@@ -163,821 +719,5833 @@ type Config struct {
 	Tasks               []Task     `json:"tasks"`
 	LastUpdate          time.Time  `json:"last_update"`
 	Version             string     `json:"version"`
+	WrittenBy           string     `json:"written_by,omitempty"`
 	GitHubSetupComplete bool       `json:"github_setup_complete,omitempty"`
+	TourComplete        bool       `json:"tour_complete,omitempty"`
+	ConfirmDeletes      *bool      `json:"confirm_deletes,omitempty"`
+	ConfirmSync         *bool      `json:"confirm_sync,omitempty"`
+	QuitSummary         *bool      `json:"quit_summary,omitempty"`
+	FocusMinutes        int        `json:"focus_minutes,omitempty"`
+	BreakMinutes        int        `json:"break_minutes,omitempty"`
+	AppTitle            string     `json:"app_title,omitempty"`
+	FooterNote          string     `json:"footer_note,omitempty"`
+	SavedFilters        []Filter   `json:"saved_filters,omitempty"`
+	ColumnView          bool       `json:"column_view,omitempty"`
+	WrapNavigation      bool       `json:"wrap_navigation,omitempty"`
+	// DateFormat is a Go time layout used to display dates (default 2006-01-02)
+	DateFormat string `json:"date_format,omitempty"`
+	// RelativeDates shows every time relative ("2 days ago") when true and
+	// absolute when false; unset keeps ages relative and timestamps absolute
+	RelativeDates *bool `json:"relative_dates,omitempty"`
+	// Hyperlinks forces OSC 8 links on or off; unset detects terminal support
+	Hyperlinks *bool `json:"hyperlinks,omitempty"`
+	// WIPLimits caps pending tasks per priority, keyed "P0".."P3"; going over warns
+	WIPLimits map[string]int `json:"wip_limits,omitempty"`
+	// ExcludeWaiting leaves waiting tasks out of the completion bar
+	ExcludeWaiting bool `json:"exclude_waiting_from_progress,omitempty"`
+	// LastView is the view open at quit (list, completed, categories), reopened on launch
+	LastView string `json:"last_view,omitempty"`
+	// SyncVisibility is private (default), public, or internal; used when creating the sync repo
+	SyncVisibility string `json:"sync_visibility,omitempty"`
+	// SyncOwner puts the sync repo under an org or other account instead of the gh user
+	SyncOwner string `json:"sync_owner,omitempty"`
+	// NotesAutosaveMs saves detail-view notes this long after the last keystroke (0 = off)
+	NotesAutosaveMs int `json:"notes_autosave_ms,omitempty"`
+	// PriorityWeights, keyed "P0".."P3", adds a weighted percentage to the progress bar (missing = 1)
+	PriorityWeights map[string]int `json:"priority_weights,omitempty"`
+	// InboxFile is a plain text file whose lines become Inbox tasks at startup (default ~/todobi-inbox.txt, "off" disables)
+	InboxFile string `json:"inbox_file,omitempty"`
+	// IdleLockMinutes hides the screen after this long without a key press (0 = off)
+	IdleLockMinutes int `json:"idle_lock_minutes,omitempty"`
+	// ProgressStyle colors the progress bar: accent, one #rrggbb color, or a #rrggbb,#rrggbb gradient (default green)
+	ProgressStyle string `json:"progress_style,omitempty"`
+	// WindowTitle shows pending counts in the terminal title (default on)
+	WindowTitle *bool `json:"window_title,omitempty"`
+	// GroupBy sections the dashboard by priority (default) or category
+	GroupBy string `json:"group_by,omitempty"`
+	// CategoryDelete is what deleting a category that still has tasks does by
+	// default: refuse, reassign (tasks move to Uncategorized), or delete (tasks too)
+	CategoryDelete string `json:"category_delete,omitempty"`
+	// DashboardPreviewCount is how many tasks each dashboard group shows (0 = all, default 5)
+	DashboardPreviewCount *int `json:"dashboard_preview_count,omitempty"`
+	// SortMode orders tasks within each category: priority (default), created, name, or neglected
+	SortMode string `json:"sort_mode,omitempty"`
+	// SortDesc reverses the active and completed lists (newest first, Z-A)
+	SortDesc bool `json:"sort_desc,omitempty"`
+	// StaleDays marks pending tasks older than this many days with ⏳ (0 = off)
+	StaleDays int `json:"stale_days,omitempty"`
+	// StaleFirst sorts stale tasks to the top of their category
+	StaleFirst bool `json:"stale_first,omitempty"`
+	// RememberCategory keeps the new-task form's default category across sessions
+	RememberCategory bool `json:"remember_last_category,omitempty"`
+	// LastCategoryID is the category the last new task went to (with RememberCategory)
+	LastCategoryID string `json:"last_category_id,omitempty"`
+	// PromptFormat is the `todobi prompt` line, with {done} {total} {pending} {p0} {doing} {waiting} {snoozed} {today}
+	PromptFormat string `json:"prompt_format,omitempty"`
+	// CompletedRetentionDays prunes older completed tasks on startup (0 = keep forever)
+	CompletedRetentionDays int `json:"completed_retention_days,omitempty"`
+
+	unknown unknownFields // settings this build doesn't know, written back as is
 }
 
-type viewMode int
-
-const (
-	listView viewMode = iota
-	categoryFormView
-	taskFormView
-	completedView
-	deleteConfirmView
-	categoryListView
-	syncConfirmView
-	pullConfirmView
-	editTaskView
-	taskDetailView
-	firstRunView
-)
-
-// syncResultMsg is sent when the GitHub sync completes
-type syncResultMsg struct {
-	success bool
-	error   string
+func (c Config) MarshalJSON() ([]byte, error) {
+	type plain Config
+	data, err := json.Marshal(plain(c))
+	if err != nil {
+		return nil, err
+	}
+	return c.unknown.appendTo(data), nil
 }
 
-// pullResultMsg is sent when the GitHub pull completes
-type pullResultMsg struct {
-	success      bool
-	error        string
-	remoteConfig *Config
-	hasConflict  bool
+// syncVisibility is the visibility flag for `gh repo create`
+func (c *Config) syncVisibility() string {
+	if c.SyncVisibility == "" {
+		return "private"
+	}
+	return strings.ToLower(c.SyncVisibility)
 }
 
-// firstRunStep tracks the first-run setup flow
-type firstRunStep int
+// checkSyncVisibility rejects a sync_visibility gh repo create doesn't
+// take, since it is passed to gh as a flag
+func (c *Config) checkSyncVisibility() error {
+	switch c.syncVisibility() {
+	case "private", "public", "internal":
+		return nil
+	}
+	return fmt.Errorf("sync_visibility %q: want private, public, or internal", c.SyncVisibility)
+}
 
-const (
-	welcomeStep firstRunStep = iota
-	hasRepoPromptStep
-	createRepoPromptStep
-	pullingStep
-	pushingStep
-	completeStep
-)
+// syncRepoPath names the sync repo for display, owner first when
+// sync_owner is set (otherwise it lives under the gh user)
+func (c *Config) syncRepoPath() string {
+	if c.SyncOwner != "" {
+		return c.SyncOwner + "/" + syncRepoName()
+	}
+	return syncRepoName()
+}
 
-// Model is the Bubble Tea model
-type model struct {
-	config             *Config
-	width              int
-	height             int
-	mode               viewMode
-	prevMode           viewMode
-	ready              bool
-	statusMsg          string
-	statusUntil        time.Time
-	categoryInput      textinput.Model
-	taskInputs         []textinput.Model
-	formFocus          int
-	list               list.Model
-	completedList      list.Model
-	categoryList       list.Model
-	taskToDelete       *Task
-	categoryToDelete   *Category
-	editingCategory    *Category
-	editingTask        *Task
-	notesTextarea      textarea.Model
-	showingSaveConfirm bool
-	originalNotes      string
-	configChanged      bool
-	syncInProgress     bool
-	pullInProgress     bool
-	remoteConfig       *Config
-	spinner            spinner.Model
-	firstRunStep       firstRunStep
-	firstRunError      string
-	activeTabIndex     int    // 0 = "All", then index into categories array + 1
-	selectedCategoryID string // "" = "All", otherwise category ID
+// groupBy is how the dashboard sections tasks: "priority" or "category"
+func (c *Config) groupBy() string {
+	if c.GroupBy == "" {
+		return "priority"
+	}
+	return c.GroupBy
 }
 
-func (m *model) getCategoryTabNames() []string {
-	tabNames := []string{"All"}
-	for _, cat := range m.config.Categories {
-		tabNames = append(tabNames, cat.Name)
+// defaultProgressColor fills the progress bar when progress_style is unset
+const defaultProgressColor = "#4caf50"
+
+// progressStyleColors parses progress_style into the bar's first and last
+// colors; a solid style returns the same color twice
+func progressStyleColors(style string) (from, to string, err error) {
+	switch style {
+	case "":
+		return defaultProgressColor, defaultProgressColor, nil
+	case "accent":
+		return style, style, nil
 	}
-	return tabNames
+	from, to, gradient := strings.Cut(style, ",")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !gradient {
+		to = from
+	}
+	for _, color := range []string{from, to} {
+		if _, ok := parseHexColor(color); !ok {
+			return "", "", fmt.Errorf("%q is not a #rrggbb color", color)
+		}
+	}
+	return from, to, nil
 }
 
-func (m model) renderTabs() string {
-	tabNames := m.getCategoryTabNames()
-	separator := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#333")).
-		Render("│")
+// parseHexColor splits "#rrggbb" into its red, green and blue channels
+func parseHexColor(color string) ([3]float64, bool) {
+	if len(color) != 7 || color[0] != '#' {
+		return [3]float64{}, false
+	}
+	v, err := strconv.ParseUint(color[1:], 16, 32)
+	if err != nil {
+		return [3]float64{}, false
+	}
+	return [3]float64{float64(v >> 16 & 0xff), float64(v >> 8 & 0xff), float64(v & 0xff)}, true
+}
 
-	// Render individual tabs
-	var renderedTabs []string
-	for i, tabName := range tabNames {
-		var style lipgloss.Style
-		if i == m.activeTabIndex {
-			style = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#4ec9b0")).
-				Bold(true).
-				Padding(0, 2)
-		} else {
-			style = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#666")).
-				Padding(0, 2)
+// progressFill is the color of each cell of a width-cell progress bar. A
+// gradient spans the whole bar, so the end color shows only near 100%.
+func (c *Config) progressFill(width int) []lipgloss.TerminalColor {
+	fill := make([]lipgloss.TerminalColor, width)
+	from, to, err := progressStyleColors(c.ProgressStyle)
+	if err != nil {
+		from, to = defaultProgressColor, defaultProgressColor
+	}
+	if from == "accent" {
+		for i := range fill {
+			fill[i] = colorAccent
 		}
-		renderedTabs = append(renderedTabs, style.Render(tabName))
+		return fill
 	}
 
-	// Wrap tabs to multiple lines based on width
-	var lines []string
-	var currentLine []string
-	currentWidth := 0
-	separatorWidth := lipgloss.Width(separator)
+	a, _ := parseHexColor(from)
+	b, _ := parseHexColor(to)
+	for i := range fill {
+		t := 0.0
+		if width > 1 {
+			t = float64(i) / float64(width-1)
+		}
+		var rgb [3]int
+		for ch := range rgb {
+			rgb[ch] = int(a[ch] + (b[ch]-a[ch])*t + 0.5)
+		}
+		fill[i] = lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2]))
+	}
+	return fill
+}
 
-	for _, tab := range renderedTabs {
-		tabWidth := lipgloss.Width(tab)
-		needsSeparator := len(currentLine) > 0
+// sortModes are the orders O cycles through
+var sortModes = []string{"priority", "created", "name", "neglected"}
 
-		// Calculate width if we add this tab
-		addedWidth := tabWidth
-		if needsSeparator {
-			addedWidth += separatorWidth
-		}
+// sortMode is how tasks are ordered within a category
+func (c *Config) sortMode() string {
+	if c.SortMode == "" {
+		return "priority"
+	}
+	return c.SortMode
+}
 
-		// Check if adding this tab would exceed width
-		if currentWidth+addedWidth > m.width && len(currentLine) > 0 {
-			// Start new line
-			lines = append(lines, strings.Join(currentLine, separator))
-			currentLine = []string{tab}
-			currentWidth = tabWidth
-		} else {
-			// Add to current line
-			currentLine = append(currentLine, tab)
-			currentWidth += addedWidth
+// sortLabel describes the sort mode and direction for the status line
+func (c *Config) sortLabel() string {
+	labels := map[string][2]string{
+		"priority":  {"P0 first", "P3 first"},
+		"created":   {"oldest first", "newest first"},
+		"name":      {"A-Z", "Z-A"},
+		"neglected": {"least recently touched first", "most recently touched first"},
+	}
+	direction := 0
+	if c.SortDesc {
+		direction = 1
+	}
+	return c.sortMode() + ", " + labels[c.sortMode()][direction]
+}
+
+// sorted turns a list's default order into the configured one. The created,
+// name, and neglected modes keep tasks grouped by category; sort_desc
+// flips it all.
+func (c *Config) sorted(less func(a, b TaskItem) bool) func(a, b TaskItem) bool {
+	switch c.sortMode() {
+	case "created":
+		less = func(a, b TaskItem) bool {
+			if a.CategoryName != b.CategoryName {
+				return a.CategoryName < b.CategoryName
+			}
+			return tieLess(a, b)
+		}
+	case "name":
+		less = func(a, b TaskItem) bool {
+			if a.CategoryName != b.CategoryName {
+				return a.CategoryName < b.CategoryName
+			}
+			if x, y := strings.ToLower(a.Content), strings.ToLower(b.Content); x != y {
+				return x < y
+			}
+			return tieLess(a, b)
+		}
+	case "neglected":
+		less = func(a, b TaskItem) bool {
+			if a.CategoryName != b.CategoryName {
+				return a.CategoryName < b.CategoryName
+			}
+			if x, y := a.lastActivity(), b.lastActivity(); !x.Equal(y) {
+				return x.Before(y)
+			}
+			return tieLess(a, b)
 		}
 	}
+	if c.SortDesc {
+		return func(a, b TaskItem) bool { return less(b, a) }
+	}
+	return less
+}
 
-	// Add remaining tabs
-	if len(currentLine) > 0 {
-		lines = append(lines, strings.Join(currentLine, separator))
+// dashboardPreview is how many of a dashboard group's total tasks to show
+func (c *Config) dashboardPreview(total int) int {
+	if c.DashboardPreviewCount == nil || *c.DashboardPreviewCount < 0 {
+		return min(total, defaultDashboardPreview)
+	}
+	if *c.DashboardPreviewCount == 0 {
+		return total
 	}
+	return min(total, *c.DashboardPreviewCount)
+}
 
-	return strings.Join(lines, "\n")
+// hyperlinks reports whether task URLs should be rendered as OSC 8 links
+func (c *Config) hyperlinks() bool {
+	if c.Hyperlinks != nil {
+		return *c.Hyperlinks
+	}
+	return terminalSupportsHyperlinks()
 }
 
-func main() {
-	// Check for seed flag
-	if len(os.Args) > 1 && os.Args[1] == "seed" {
-		cfg := seedWeekendTasks()
-		if err := saveConfig(cfg); err != nil {
-			fmt.Printf("Error seeding config: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Println("Config seeded with weekend tasks!")
-		os.Exit(0)
+// categoryDeleteModes are what deleting a category can do with its tasks
+var categoryDeleteModes = []string{"refuse", "reassign", "delete"}
+
+// categoryDelete is the default for deleting a category with tasks
+func (c *Config) categoryDelete() string {
+	if c.CategoryDelete == "" {
+		return "refuse"
 	}
+	return c.CategoryDelete
+}
 
-	// Check for pull flag (for initial setup on new machine)
-	if len(os.Args) > 1 && os.Args[1] == "--pull" {
-		fmt.Println("Pulling config from GitHub...")
-		if err := pullConfigFromGitHub(); err != nil {
-			fmt.Printf("Error pulling config: %v\n", err)
-			os.Exit(1)
+// uncategorizedName is the category reassigned tasks land in
+const uncategorizedName = "Uncategorized"
+
+// uncategorizedID returns the Uncategorized category, creating it if needed
+func (c *Config) uncategorizedID() string {
+	for _, cat := range c.Categories {
+		if strings.EqualFold(cat.Name, uncategorizedName) {
+			return cat.ID
 		}
-		fmt.Println("Config pulled successfully!")
-		os.Exit(0)
 	}
+	cat := Category{ID: generateID(), Name: uncategorizedName}
+	c.Categories = append(c.Categories, cat)
+	return cat.ID
+}
 
-	cfg, err := loadConfig()
-	if err != nil {
-		cfg = defaultConfig()
-		if err := saveConfig(cfg); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+// categoryName looks up a category's display name by ID
+func (c *Config) categoryName(id string) string {
+	for _, cat := range c.Categories {
+		if cat.ID == id {
+			return cat.Name
 		}
 	}
+	return "Unknown"
+}
 
-	m := model{
-		config:        cfg,
-		categoryInput: textinput.New(),
-		taskInputs:    make([]textinput.Model, 2),
-		notesTextarea: textarea.New(),
-		firstRunStep:  welcomeStep,
+// dateFormat returns the layout used to display dates
+func (c *Config) dateFormat() string {
+	if c.DateFormat == "" {
+		return "2006-01-02"
 	}
+	return c.DateFormat
+}
 
-	// Check if this is first run (GitHub not set up yet)
-	if !cfg.GitHubSetupComplete {
-		m.mode = firstRunView
-	}
+// parseDateInput accepts ISO (2006-01-02), US (01/02/2006), and relative
+// dates (today, tomorrow, +3d, +2w, next monday) and returns local midnight
+func parseDateInput(s string) (time.Time, error) {
+	return parseDateInputAt(s, time.Now())
+}
 
-	m.categoryInput.Placeholder = "Category name"
-	m.categoryInput.CharLimit = 50
+func parseDateInputAt(s string, now time.Time) (time.Time, error) {
+	input := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	today := startOfDay(now)
+
+	switch input {
+	case "":
+		return time.Time{}, fmt.Errorf("enter a date")
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
 
-	m.taskInputs[0] = textinput.New()
-	m.taskInputs[0].Placeholder = "Task content"
-	m.taskInputs[0].CharLimit = 200
+	for _, layout := range []string{"2006-01-02", "01/02/2006", "1/2/2006"} {
+		if t, err := time.ParseInLocation(layout, input, now.Location()); err == nil {
+			return t, nil
+		}
+	}
 
-	m.taskInputs[1] = textinput.New()
-	m.taskInputs[1].Placeholder = "Priority (0-3)"
-	m.taskInputs[1].CharLimit = 1
+	// +Nd / +Nw offsets from today
+	if strings.HasPrefix(input, "+") && len(input) > 2 {
+		var n int
+		unit := input[len(input)-1]
+		if _, err := fmt.Sscanf(input[1:len(input)-1], "%d", &n); err == nil && n > 0 {
+			switch unit {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			}
+		}
+	}
 
-	m.notesTextarea.Placeholder = "Add notes here..."
-	m.notesTextarea.CharLimit = 2000
-	m.notesTextarea.SetHeight(10)
+	// Weekday names mean the next such day, never today
+	day := strings.TrimPrefix(input, "next ")
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if day == name || day == name[:3] {
+			offset := (int(wd) - int(today.Weekday()) + 7) % 7
+			if offset == 0 {
+				offset = 7
+			}
+			return today.AddDate(0, 0, offset), nil
+		}
+	}
 
-	// Initialize lists
-	m.list = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
-	// m.list.Title = "Tasks"  // Removed - using tabs instead
-	m.list.SetShowTitle(false) // Hide title since we're using tabs
-	m.list.SetShowStatusBar(false)
-	m.list.SetFilteringEnabled(false)
+	return time.Time{}, fmt.Errorf("can't read %q as a date (try 2006-01-02, 01/02/2006, tomorrow, +3d, next monday)", s)
+}
 
-	// Disable default keybindings we don't want
-	m.list.KeyMap.GoToStart.SetEnabled(false)
-	m.list.KeyMap.GoToEnd.SetEnabled(false)
+// parseISO8601Duration reads ISO-8601 durations with fixed-length parts:
+// weeks and days before the T, hours, minutes and seconds after it (P1W,
+// P3D, PT2H, P1DT12H). Years and months are rejected since their length
+// depends on the calendar.
+func parseISO8601Duration(s string) (time.Duration, error) {
+	input := strings.ToUpper(strings.TrimSpace(s))
+	if len(input) < 3 || input[0] != 'P' {
+		return 0, fmt.Errorf("%q is not an ISO-8601 duration", s)
+	}
 
-	m.list.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "new task")),
-			key.NewBinding(key.WithKeys("C"), key.WithHelp("C", "new category")),
-			key.NewBinding(key.WithKeys("x", "space"), key.WithHelp("x/space", "toggle done")),
-			key.NewBinding(key.WithKeys("enter", "i"), key.WithHelp("enter/i", "view details")),
-			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	timeUnits := map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+
+	var total time.Duration
+	digits := ""
+	parts, dateParts := 0, 0
+	inTime := false
+	for i := 1; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits += string(c)
+		case c == 'T' && digits == "" && !inTime:
+			units, inTime = timeUnits, true
+			dateParts = parts
+		default:
+			unit, ok := units[c]
+			if !ok || digits == "" {
+				if c == 'Y' || (c == 'M' && !inTime) {
+					return 0, fmt.Errorf("%q: years and months have no fixed length; use weeks or days", s)
+				}
+				return 0, fmt.Errorf("%q is not an ISO-8601 duration", s)
+			}
+			n, err := strconv.Atoi(digits)
+			if err != nil {
+				return 0, fmt.Errorf("%q is not an ISO-8601 duration", s)
+			}
+			total += time.Duration(n) * unit
+			delete(units, c) // each part at most once
+			digits = ""
+			parts++
 		}
 	}
-	m.list.AdditionalFullHelpKeys = func() []key.Binding {
-		return []key.Binding{
-			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "categories")),
-			key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "completed")),
-			key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "sync github")),
-			key.NewBinding(key.WithKeys(""), key.WithHelp("", "todobi - simple terminal task manager - builtbywilly.com")),
-		}
+	if digits != "" || parts == 0 || (inTime && parts == dateParts) {
+		return 0, fmt.Errorf("%q is not an ISO-8601 duration", s)
 	}
+	return total, nil
+}
 
-	m.completedList = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
-	m.completedList.Title = "Completed Tasks"
-	m.completedList.SetShowStatusBar(false)
-	m.completedList.SetFilteringEnabled(false)
+// Filter is a named smart list; every set field must match
+type Filter struct {
+	Name        string    `json:"name"`
+	MaxPriority *Priority `json:"max_priority,omitempty"` // P0..this priority
+	CategoryID  string    `json:"category_id,omitempty"`
+	Contains    string    `json:"contains,omitempty"` // case-insensitive, content or notes
+	MinAgeDays  int       `json:"min_age_days,omitempty"`
+	MaxAgeDays  int       `json:"max_age_days,omitempty"`
 
-	m.categoryList = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
-	m.categoryList.Title = "Categories"
-	m.categoryList.SetShowStatusBar(false)
-	m.categoryList.SetFilteringEnabled(false)
+	unknown unknownFields // fields this build doesn't know, written back as is
+}
 
-	// Initialize spinner
-	m.spinner = spinner.New()
-	m.spinner.Spinner = spinner.Pulse
-	m.spinner.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#4ec9b0"))
+func (f Filter) MarshalJSON() ([]byte, error) {
+	type plain Filter
+	data, err := json.Marshal(plain(f))
+	if err != nil {
+		return nil, err
+	}
+	return f.unknown.appendTo(data), nil
+}
 
-	// Initialize category tabs
-	m.activeTabIndex = 0      // Start with "All" tab
-	m.selectedCategoryID = "" // Start with "All" selected
+// matchesFilter reports whether a task satisfies every condition in f
+func matchesFilter(t Task, f Filter) bool {
+	if f.MaxPriority != nil && t.Priority > *f.MaxPriority {
+		return false
+	}
+	if f.CategoryID != "" && t.CategoryID != f.CategoryID {
+		return false
+	}
+	if f.Contains != "" {
+		needle := strings.ToLower(f.Contains)
+		if !strings.Contains(strings.ToLower(t.Content), needle) &&
+			!strings.Contains(strings.ToLower(t.Notes), needle) {
+			return false
+		}
+	}
+	ageDays := int(time.Since(t.CreatedAt).Hours() / 24)
+	if f.MinAgeDays > 0 && ageDays < f.MinAgeDays {
+		return false
+	}
+	if f.MaxAgeDays > 0 && ageDays > f.MaxAgeDays {
+		return false
+	}
+	return true
+}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+// appTitle is the list title shown in the header bar
+func (c *Config) appTitle() string {
+	if c.AppTitle != "" {
+		return c.AppTitle
 	}
+	return "Tasks"
 }
 
-// Config operations
-func loadConfig() (*Config, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
+// footerNote is the branding line shown in the full help
+func (c *Config) footerNote() string {
+	if c.FooterNote != "" {
+		return c.FooterNote
 	}
+	return "todobi - simple terminal task manager - builtbywilly.com"
+}
 
-	path := filepath.Join(home, configFileName)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// confirmDeletes reports whether deletes need a y/n confirmation (default true)
+func (c *Config) confirmDeletes() bool {
+	return c.ConfirmDeletes == nil || *c.ConfirmDeletes
+}
+
+// confirmSync reports whether G asks before pushing to GitHub (default true)
+func (c *Config) confirmSync() bool {
+	return c.ConfirmSync == nil || *c.ConfirmSync
+}
+
+// quitSummary reports whether quitting prints today's completions (default true)
+func (c *Config) quitSummary() bool {
+	return c.QuitSummary == nil || *c.QuitSummary
+}
+
+// showWindowTitle reports whether the terminal title tracks pending counts
+func (c *Config) showWindowTitle() bool {
+	return c.WindowTitle == nil || *c.WindowTitle
+}
+
+// isStale reports whether a pending task has lingered past stale_days
+func (c *Config) isStale(t Task, now time.Time) bool {
+	return c.StaleDays > 0 && !t.Done && now.Sub(t.CreatedAt) > time.Duration(c.StaleDays)*24*time.Hour
+}
+
+// promptFormat is the template for `todobi prompt`
+func (c *Config) promptFormat() string {
+	if c.PromptFormat == "" {
+		return "✓{done}/{total} ⚠{waiting} !{p0}"
 	}
+	return c.PromptFormat
+}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, err
+// focusDuration is the length of a focus session (default 25 minutes)
+func (c *Config) focusDuration() time.Duration {
+	if c.FocusMinutes <= 0 {
+		return 25 * time.Minute
 	}
+	return time.Duration(c.FocusMinutes) * time.Minute
+}
 
-	return &cfg, nil
+// idleLock is how long without input before the screen locks (0 = never)
+func (c *Config) idleLock() time.Duration {
+	return time.Duration(max(c.IdleLockMinutes, 0)) * time.Minute
 }
 
-func saveConfig(cfg *Config) error {
+// inboxPath is the inbox file to import, or "" when the inbox is off
+func (c *Config) inboxPath() string {
+	path := c.InboxFile
+	if path == "off" {
+		return ""
+	}
 	home, err := os.UserHomeDir()
-	if err != nil {
-		return err
+	if path == "" {
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(home, "todobi-inbox.txt")
+	}
+	if err == nil && (path == "~" || strings.HasPrefix(path, "~/")) {
+		path = filepath.Join(home, path[1:])
 	}
+	return path
+}
 
-	cfg.LastUpdate = time.Now()
-	path := filepath.Join(home, configFileName)
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		return err
+// breakDuration is the break that follows a focus session (default 5 minutes)
+func (c *Config) breakDuration() time.Duration {
+	if c.BreakMinutes <= 0 {
+		return 5 * time.Minute
 	}
+	return time.Duration(c.BreakMinutes) * time.Minute
+}
 
-	return os.WriteFile(path, data, 0644)
+// wip returns how many tasks are pending at priority p and its WIP limit
+// (0 = no limit)
+func (c *Config) wip(p Priority) (pending, limit int) {
+	for _, task := range c.Tasks {
+		if !task.Done && !task.Backlog && task.Priority == p {
+			pending++
+		}
+	}
+	return pending, c.WIPLimits[p.String()]
 }
 
-func (m *model) saveConfigAndMarkChanged() {
-	if err := saveConfig(m.config); err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Failed to save config: %v\n", err)
-		m.setStatus("Error saving: " + err.Error())
-		return
+// overWIP returns a warning like "P0 over limit: 6/5" when p exceeds its limit
+func (c *Config) overWIP(p Priority) (string, bool) {
+	pending, limit := c.wip(p)
+	if limit <= 0 || pending <= limit {
+		return "", false
 	}
-	m.configChanged = true
+	return fmt.Sprintf("%s over limit: %d/%d", p, pending, limit), true
 }
 
-func defaultConfig() *Config {
-	return &Config{
-		Version: "1.3.0",
-		Categories: []Category{
-			{ID: "work", Name: "Work"},
-			{ID: "personal", Name: "Personal"},
-		},
-		Tasks: []Task{
-			{
-				ID:         "1",
-				Content:    "Press 'C' to create category",
-				CategoryID: "work",
-				Priority:   P1High,
-				CreatedAt:  time.Now(),
-			},
-			{
-				ID:         "2",
-				Content:    "Press 'T' to create task",
-				CategoryID: "work",
-				Priority:   P2Medium,
-				CreatedAt:  time.Now(),
-			},
-			{
+// completedOn counts tasks completed on the same calendar day as now
+func (c *Config) completedOn(now time.Time) int {
+	today := startOfDay(now)
+	count := 0
+	for _, task := range c.Tasks {
+		if task.Done && !task.CompletedAt.Before(today) && task.CompletedAt.Before(today.AddDate(0, 0, 1)) {
+			count++
+		}
+	}
+	return count
+}
+
+type viewMode int
+
+const (
+	listView viewMode = iota
+	categoryFormView
+	taskFormView
+	completedView
+	deleteConfirmView
+	categoryListView
+	syncConfirmView
+	pullConfirmView
+	editTaskView
+	taskDetailView
+	firstRunView
+	snoozedView
+	reloadConfirmView
+	dedupeConfirmView
+	snoozeDateView
+	dashboardView
+	categoryMergeView
+	completeCategoryView
+	syncErrorView
+	resolveConflictsView
+	createdDateView
+	categoryNotesView
+	tourView
+	backlogView
+)
+
+// lastViewNames are the views remembered across launches, by config name
+var lastViewNames = map[viewMode]string{
+	listView:         "list",
+	completedView:    "completed",
+	categoryListView: "categories",
+}
+
+// syncResultMsg is sent when the GitHub sync completes
+type syncResultMsg struct {
+	success bool
+	error   string
+	details string // raw gh/git output behind error, shown with E
+}
+
+// pullResultMsg is sent when the GitHub pull completes
+type pullResultMsg struct {
+	success      bool
+	error        string
+	remoteConfig *Config
+	hasConflict  bool
+	noRemote     bool   // the repo or its config doesn't exist yet
+	details      string // raw gh/git output behind error, shown with E
+}
+
+// dedupeScanMsg carries the result of a background duplicate scan
+type dedupeScanMsg struct {
+	duplicates [][]Task
+}
+
+// findDuplicatesCmd scans a snapshot of the tasks off the UI goroutine so
+// large configs don't freeze the screen
+func findDuplicatesCmd(tasks []Task) tea.Cmd {
+	return func() tea.Msg {
+		return dedupeScanMsg{duplicates: findDuplicates(&Config{Tasks: tasks})}
+	}
+}
+
+// signalMsg reports a termination signal so Update can save before exiting
+type signalMsg struct {
+	signal os.Signal
+}
+
+// focusTickMsg drives the focus timer countdown; it only runs while a
+// focus session or break is active
+type focusTickMsg time.Time
+
+func focusTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return focusTickMsg(t)
+	})
+}
+
+// notesAutosaveMsg fires after a pause in note typing; only the latest
+// edit's message (matching seq) saves
+type notesAutosaveMsg struct {
+	seq int
+}
+
+// tickMsg drives periodic work such as waking snoozed tasks
+type tickMsg time.Time
+
+func tickCmd() tea.Cmd {
+	return tea.Tick(tickInterval, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// firstRunStep tracks the first-run setup flow
+type firstRunStep int
+
+const (
+	welcomeStep firstRunStep = iota
+	hasRepoPromptStep
+	createRepoPromptStep
+	pullingStep
+	pushingStep
+	completeStep
+)
+
+// tourSteps are the overlays of the onboarding tour shown after first run
+var tourSteps = []struct {
+	title string
+	keys  string
+	text  string
+}{
+	{"Create a task", "T", "Add a task to the current category. Fill in the content, priority and an optional URL, then press enter."},
+	{"Categories", "c / C / tab", "c manages categories, C creates one, and tab/shift+tab move between the category tabs."},
+	{"Complete tasks", "x / space", "Toggle the selected task done. v shows what you've completed."},
+	{"Sync", "G / g", "G pushes your tasks to GitHub and g pulls them on another machine."},
+	{"Everything else", "?", "Press ? any time for the full list of key bindings."},
+}
+
+// Model is the Bubble Tea model
+type model struct {
+	config             *Config
+	width              int
+	height             int
+	mode               viewMode
+	prevMode           viewMode
+	ready              bool
+	statusMsg          string
+	statusUntil        time.Time
+	categoryInput      textinput.Model
+	taskInputs         []textinput.Model
+	formFocus          int
+	list               list.Model
+	completedList      list.Model
+	snoozedList        list.Model
+	backlogList        list.Model
+	categoryList       list.Model
+	taskToDelete       *Task
+	categoryToDelete   *Category
+	editingCategory    *Category
+	hideLowPriority    bool
+	mergeSource        *Category
+	mergeCursor        int
+	mergeConfirm       bool
+	focusTaskID        string
+	focusContent       string
+	focusUntil         time.Time
+	breakUntil         time.Time
+	dashCursor         int    // index into dashboardTasks()
+	busy               string // label of a running background operation
+	pullSelecting      bool
+	pullCursor         int
+	pullSelected       map[string]bool
+	conflictIDs        []string        // tasks changed on both sides, from diffConfigs
+	conflictIndex      int             // the conflict on screen
+	takeRemote         map[string]bool // per-task choice; false keeps local
+	completedToday     int
+	hiddenLowCount     int
+	doneCount          int // progress for the header bar, refreshed by updateLists
+	totalCount         int
+	dateInput          textinput.Model
+	dateErr            string
+	contentErr         string // task and edit forms: why focus can't leave Content
+	categoryColorIndex int
+	renamingCategory   bool
+	windowTitle        string // set by updateLists, sent by Update when it changes
+	syncError          string // last sync/pull failure, explained
+	syncDetails        string // raw output behind syncError
+	readonly           bool   // presentation mode: browse only, no changes or syncs
+	notesEditSeq       int    // bumped per notes edit to debounce autosave
+	notesAutosaved     bool   // the notes on screen were saved by autosave
+	searchInput        textinput.Model
+	searching          bool     // the search input has focus
+	searchQuery        string   // narrows the active list by content and notes
+	searchHistory      []string // this session's queries, oldest first
+	searchRecall       int      // index into searchHistory; len means the draft
+	searchDraft        string   // what was typed before recalling history
+	lastCategoryID     string   // category of the last task added, the form's default
+	fireDrill          bool     // ! shows only pending P0 tasks, across every category
+	visual             bool     // V range selection in the list view
+	visualAnchor       string   // ID of the task where the selection started
+	visualOp           string   // operator awaiting input: "d" (confirm) or "m" (category)
+	linkChoices        []string // links o offered, waiting for their number
+	editingTask        *Task
+	notesTextarea      textarea.Model
+	showingSaveConfirm bool
+	originalNotes      string
+	originalPriority   Priority // detail view priority before alt+=/alt+- changed it
+	notesCategoryID    string   // category whose notes categoryNotesView edits
+	configChanged      bool
+	syncInProgress     bool
+	pullInProgress     bool
+	remoteConfig       *Config
+	spinner            spinner.Model
+	firstRunStep       firstRunStep
+	firstRunError      string
+	tourStep           int    // index into tourSteps while tourView is open
+	activeTabIndex     int    // 0 = "All", then index into categories array + 1
+	selectedCategoryID string // "" = "All", otherwise category ID
+	nextSnoozeWake     time.Time
+	lastInput          time.Time          // last key press, for idle_lock_minutes
+	locked             bool               // idle lock is hiding the screen
+	syncCancel         context.CancelFunc // aborts the in-flight sync or pull
+	configLock         *os.File           // held while the TUI may save; see lockConfig
+	activeFilterIndex  int                // 0 = no smart list, otherwise index into SavedFilters + 1
+	pendingReload      *Config            // disk config awaiting reload confirmation
+	duplicates         [][]Task           // duplicate groups awaiting confirmation, keeper first
+}
+
+// activeFilter returns the selected smart list, if any
+func (m model) activeFilter() (Filter, bool) {
+	if m.activeFilterIndex == 0 || m.activeFilterIndex > len(m.config.SavedFilters) {
+		return Filter{}, false
+	}
+	return m.config.SavedFilters[m.activeFilterIndex-1], true
+}
+
+// listTitle is the header bar text, decorated with the active smart list
+func (m model) listTitle() string {
+	if m.fireDrill {
+		return "🔥 P0 FIRE DRILL"
+	}
+	title := m.config.appTitle()
+	if workspace != "" {
+		title = "[" + workspace + "] " + title
+	}
+	if f, ok := m.activeFilter(); ok {
+		title += " · " + f.Name
+	}
+	if m.searchQuery != "" {
+		title += " · /" + m.searchQuery
+	}
+	if m.hideLowPriority {
+		title += fmt.Sprintf(" (hiding low priority, %d hidden)", m.hiddenLowCount)
+	}
+	if m.totalCount == 0 {
+		return title
+	}
+
+	// "Tasks — 60% (12/20)", dropping the percentage and then the counts
+	// when the bar is too narrow for them
+	percent := m.doneCount * 100 / m.totalCount
+	for _, progress := range []string{
+		fmt.Sprintf(" — %d%% (%d/%d)", percent, m.doneCount, m.totalCount),
+		fmt.Sprintf(" — %d/%d", m.doneCount, m.totalCount),
+	} {
+		if m.width == 0 || lipgloss.Width(title+progress) <= m.width-2 {
+			return title + progress
+		}
+	}
+	return title
+}
+
+func (m *model) getCategoryTabNames() []string {
+	tabNames := []string{"All"}
+	for _, cat := range m.config.Categories {
+		tabNames = append(tabNames, cat.Name)
+	}
+	return tabNames
+}
+
+func (m model) renderTabs() string {
+	tabNames := m.getCategoryTabNames()
+	separator := lipgloss.NewStyle().
+		Foreground(colorFaint).
+		Render("│")
+
+	// Render individual tabs
+	var renderedTabs []string
+	for i, tabName := range tabNames {
+		var style lipgloss.Style
+		if i == m.activeTabIndex {
+			style = lipgloss.NewStyle().
+				Foreground(colorAccent).
+				Bold(true).
+				Padding(0, 2)
+		} else {
+			style = lipgloss.NewStyle().
+				Foreground(colorMuted).
+				Padding(0, 2)
+		}
+		renderedTabs = append(renderedTabs, style.Render(tabName))
+	}
+
+	// Wrap tabs to multiple lines based on width
+	var lines []string
+	var currentLine []string
+	currentWidth := 0
+	separatorWidth := lipgloss.Width(separator)
+
+	for _, tab := range renderedTabs {
+		tabWidth := lipgloss.Width(tab)
+		needsSeparator := len(currentLine) > 0
+
+		// Calculate width if we add this tab
+		addedWidth := tabWidth
+		if needsSeparator {
+			addedWidth += separatorWidth
+		}
+
+		// Check if adding this tab would exceed width
+		if currentWidth+addedWidth > m.width && len(currentLine) > 0 {
+			// Start new line
+			lines = append(lines, strings.Join(currentLine, separator))
+			currentLine = []string{tab}
+			currentWidth = tabWidth
+		} else {
+			// Add to current line
+			currentLine = append(currentLine, tab)
+			currentWidth += addedWidth
+		}
+	}
+
+	// Add remaining tabs
+	if len(currentLine) > 0 {
+		lines = append(lines, strings.Join(currentLine, separator))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func main() {
+	args := parseGlobalFlags(os.Args[1:])
+
+	// Check for seed flag
+	if len(args) > 0 && args[0] == "seed" {
+		cfg := seedWeekendTasks()
+		if err := saveConfig(cfg); err != nil {
+			fmt.Fprintf(infoOut(), "Error seeding config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(infoOut(), "Config seeded with weekend tasks!")
+		os.Exit(0)
+	}
+
+	// Check for version flag or command
+	if len(args) > 0 && (args[0] == "--version" || args[0] == "version") {
+		runVersion()
+		os.Exit(0)
+	}
+
+	// Check for pull flag (for initial setup on new machine)
+	if len(args) > 0 && args[0] == "--pull" {
+		fmt.Println("Pulling config from GitHub...")
+		if err := pullConfigFromGitHub(); err != nil {
+			fmt.Printf("Error pulling config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Config pulled successfully!")
+		os.Exit(0)
+	}
+
+	// Check for stats command (--json for scripts and dashboards)
+	if len(args) > 0 && args[0] == "stats" {
+		if err := runStats(args[1:]); err != nil {
+			fmt.Fprintf(infoOut(), "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for path and info commands (where the config lives, without the TUI)
+	if len(args) > 0 && (args[0] == "path" || args[0] == "info") {
+		if err := runInfo(args[0] == "path"); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for validate command
+	if len(args) > 0 && args[0] == "validate" {
+		if err := runValidate(); err != nil {
+			fmt.Fprintf(infoOut(), "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for dedupe command
+	if len(args) > 0 && args[0] == "dedupe" {
+		if err := runDedupe(args[1:]); err != nil {
+			fmt.Fprintf(infoOut(), "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for prompt command (one terse line for PS1/tmux; silent on error)
+	if len(args) > 0 && args[0] == "prompt" {
+		if err := runPrompt(); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Hidden: completion scripts call this for category names, task IDs, and priorities
+	if len(args) > 0 && args[0] == "__complete" {
+		if err := runComplete(args[1:]); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for completion command (shell completion script on stdout)
+	if len(args) > 0 && args[0] == "completion" {
+		if err := runCompletion(args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for serve command (HTTP+JSON API over the config)
+	if len(args) > 0 && args[0] == "serve" {
+		if err := runServe(args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for sync-issues command (complete tasks whose GitHub issue closed)
+	if len(args) > 0 && args[0] == "sync-issues" {
+		if err := runSyncIssues(args[1:]); err != nil {
+			fmt.Fprintf(infoOut(), "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for pick command (one-shot fuzzy picker, choice on stdout)
+	if len(args) > 0 && args[0] == "pick" {
+		if err := runPick(args[1:]); err != nil {
+			if !errors.Is(err, errPickCancelled) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for list command (pending tasks, one per line)
+	if len(args) > 0 && args[0] == "list" {
+		if err := runList(); err != nil {
+			fmt.Fprintf(infoOut(), "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for digest command (plain text or --html on stdout)
+	if len(args) > 0 && args[0] == "digest" {
+		if err := runDigest(args[1:]); err != nil {
+			fmt.Fprintf(infoOut(), "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for export command (Markdown task list on stdout)
+	if len(args) > 0 && args[0] == "export" {
+		if err := runExport(args[1:]); err != nil {
+			fmt.Fprintf(infoOut(), "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for log command (completed tasks by day, for standups)
+	if len(args) > 0 && args[0] == "log" {
+		if err := runLog(args[1:]); err != nil {
+			fmt.Fprintf(infoOut(), "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for backup command (full config on stdout)
+	if len(args) > 0 && args[0] == "backup" {
+		if err := runBackup(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Check for restore command (backup on stdin, validated first)
+	if len(args) > 0 && args[0] == "restore" {
+		if err := runRestore(os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// The TUI reads keys from stdin, so it can't also read the config there
+	if stdioConfig() {
+		fmt.Fprintln(os.Stderr, "Error: --config - only works with list, log, prompt, stats, digest, export, validate, dedupe, sync-issues, and seed")
+		os.Exit(1)
+	}
+
+	// Handle termination ourselves so a closed terminal or kill still saves.
+	// Signals arriving before the program starts wait in the channel.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	// Lock before loading so a serve write can't land between the load
+	// and this session's first save
+	var lock *os.File
+	var lockErr error
+	if !readonlyFlag {
+		lock, lockErr = sessionLock()
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		cfg = defaultConfig()
+		if err := saveConfig(cfg); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Drop completed tasks past the retention window, once per launch
+	pruned := 0
+	if !readonlyFlag {
+		pruned = pruneCompleted(cfg, time.Now())
+	}
+
+	// Pull in lines captured to the inbox file by other apps, deleting
+	// the claimed copy only once they're safely saved
+	imported, inboxPath := 0, ""
+	if !readonlyFlag {
+		if imported, inboxPath, err = importInbox(cfg, time.Now()); err != nil {
+			fmt.Printf("Warning: inbox not imported: %v\n", err)
+		}
+	}
+	if pruned > 0 || imported > 0 {
+		if err := saveConfig(cfg); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if imported > 0 {
+		if err := os.Remove(inboxPath); err != nil {
+			fmt.Printf("Warning: inbox imported but not cleared: %v\n", err)
+		}
+	}
+
+	m := model{
+		config:        cfg,
+		categoryInput: textinput.New(),
+		dateInput:     textinput.New(),
+		searchInput:   textinput.New(),
+		taskInputs:    make([]textinput.Model, 3),
+		notesTextarea: textarea.New(),
+		firstRunStep:  welcomeStep,
+		readonly:      readonlyFlag,
+		lastInput:     time.Now(),
+		configLock:    lock,
+	}
+
+	if pruned > 0 {
+		m.configChanged = true
+		m.setStatus(fmt.Sprintf("Pruned %d completed tasks older than %d days", pruned, cfg.CompletedRetentionDays))
+	}
+	if imported > 0 {
+		m.configChanged = true
+		m.setStatus(fmt.Sprintf("Imported %d tasks from inbox", imported))
+	}
+	if warning := newerConfigWarning(cfg); warning != "" {
+		m.setStatus("⚠ " + warning)
+	}
+	if lockErr != nil {
+		m.setStatus(lockStatus(lockErr))
+	}
+
+	// Check if this is first run (GitHub not set up yet); a read-only
+	// session leaves setup for later
+	if !cfg.GitHubSetupComplete && !m.readonly {
+		m.mode = firstRunView
+	} else {
+		if cfg.RememberCategory {
+			m.lastCategoryID = cfg.LastCategoryID
+		}
+		// Reopen the view the last session ended in
+		for mode, name := range lastViewNames {
+			if cfg.LastView == name {
+				m.mode = mode
+			}
+		}
+	}
+
+	m.categoryInput.Placeholder = "Category name"
+	m.categoryInput.CharLimit = 50
+
+	m.searchInput.Prompt = "/"
+	m.searchInput.Placeholder = "search tasks"
+	m.searchInput.CharLimit = 100
+
+	m.dateInput.Placeholder = "tomorrow"
+	m.dateInput.CharLimit = 30
+
+	m.taskInputs[0] = textinput.New()
+	m.taskInputs[0].Placeholder = "Task content"
+	m.taskInputs[0].CharLimit = 200
+
+	m.taskInputs[1] = textinput.New()
+	m.taskInputs[1].Placeholder = "Priority (0-3)"
+	m.taskInputs[1].CharLimit = 1
+
+	m.taskInputs[2] = textinput.New()
+	m.taskInputs[2].Placeholder = "https://... (optional, several allowed)"
+	m.taskInputs[2].CharLimit = 2000
+
+	m.notesTextarea.Placeholder = "Add notes here..."
+	m.notesTextarea.CharLimit = 2000
+	m.notesTextarea.SetHeight(10)
+
+	// Initialize lists
+	m.list = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	m.list.Title = cfg.appTitle()
+	m.list.SetShowTitle(false) // Title is drawn in the header bar above the tabs
+	m.list.SetShowStatusBar(false)
+	m.list.SetFilteringEnabled(false)
+
+	// Disable default keybindings we don't want
+	m.list.KeyMap.GoToStart.SetEnabled(false)
+	m.list.KeyMap.GoToEnd.SetEnabled(false)
+
+	m.list.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "new task")),
+			key.NewBinding(key.WithKeys("C"), key.WithHelp("C", "new category")),
+			key.NewBinding(key.WithKeys("x", "space"), key.WithHelp("x/space", "toggle done")),
+			key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "cycle state")),
+			key.NewBinding(key.WithKeys("enter", "i"), key.WithHelp("enter/i", "view details")),
+			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+		}
+	}
+	// The list's help (? toggles the full panel) is the one place every
+	// list-view key is listed; the footer only points at it
+	m.list.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("tab", "shift+tab"), key.WithHelp("tab/shift+tab", "category tabs")),
+			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "categories")),
+			key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "completed")),
+			key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "complete category")),
+			key.NewBinding(key.WithKeys("shift+left", "shift+right"), key.WithHelp("shift+←/→", "change category")),
+			key.NewBinding(key.WithKeys("z", "Z"), key.WithHelp("z/Z", "snooze day/week")),
+			key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "snooze until date")),
+			key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "snoozed")),
+			key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "to/from backlog")),
+			key.NewBinding(key.WithKeys("B"), key.WithHelp("B", "backlog")),
+			key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+			key.NewBinding(key.WithKeys("V"), key.WithHelp("V", "select range")),
+			key.NewBinding(key.WithKeys("!"), key.WithHelp("!", "P0 fire drill")),
+			key.NewBinding(key.WithKeys("F"), key.WithHelp("F", "next smart list")),
+			key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "hide low priority")),
+			key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "resort")),
+			key.NewBinding(key.WithKeys("O", "~"), key.WithHelp("O/~", "sort mode/direction")),
+			key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "dashboard")),
+			key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "focus timer")),
+			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "column view")),
+			key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "remove duplicates")),
+			key.NewBinding(key.WithKeys("W"), key.WithHelp("W", "next workspace")),
+			key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "sync github")),
+			key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "pull github")),
+			key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "last sync error")),
+			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reload config")),
+			key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "read-only mode")),
+			key.NewBinding(key.WithKeys(""), key.WithHelp("", cfg.footerNote())),
+		}
+	}
+
+	m.completedList = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	m.completedList.Title = "Completed Tasks"
+	m.completedList.SetShowStatusBar(false)
+	m.completedList.SetFilteringEnabled(false)
+
+	m.snoozedList = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	m.snoozedList.Title = "Snoozed Tasks"
+	m.snoozedList.SetShowStatusBar(false)
+	m.snoozedList.SetFilteringEnabled(false)
+
+	m.backlogList = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	m.backlogList.Title = "Backlog: Someday/Maybe"
+	m.backlogList.SetShowStatusBar(false)
+	m.backlogList.SetFilteringEnabled(false)
+
+	m.categoryList = list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	m.categoryList.Title = "Categories"
+	m.categoryList.SetShowStatusBar(false)
+	m.categoryList.SetFilteringEnabled(false)
+
+	m.applyListDelegates()
+	if m.mode == categoryListView {
+		m.updateCategoryList()
+	}
+
+	// Initialize spinner
+	m.spinner = spinner.New()
+	m.spinner.Spinner = spinner.Pulse
+	m.spinner.Style = lipgloss.NewStyle().Foreground(colorAccent)
+
+	// Initialize category tabs
+	m.activeTabIndex = 0      // Start with "All" tab
+	m.selectedCategoryID = "" // Start with "All" selected
+
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithoutSignalHandler())
+	go func() {
+		p.Send(signalMsg{<-sigs})
+	}()
+
+	final, err := p.Run()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// A little encouragement once the alt screen is gone
+	if fm, ok := final.(model); ok && fm.completedToday > 0 {
+		if fm.completedToday == 1 {
+			fmt.Println("You completed 1 task today 🎉")
+		} else {
+			fmt.Printf("You completed %d tasks today 🎉\n", fm.completedToday)
+		}
+	}
+}
+
+// Build information, set by goreleaser through -ldflags "-X main.version=..."
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+// workspace is the active task set; "" is the default ~/.todobi.conf
+var workspace string
+
+// readonlyFlag is --readonly: start in presentation mode with no way out
+var readonlyFlag bool
+
+// configOverride is the --config path; "-" reads the config from stdin and
+// writes any changes to stdout
+var configOverride string
+
+// stdioConfig reports whether the config is piped through stdin/stdout
+func stdioConfig() bool {
+	return configOverride == "-"
+}
+
+// infoOut is where CLI commands print progress, kept off stdout when
+// stdout carries the config
+func infoOut() io.Writer {
+	if stdioConfig() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// parseGlobalFlags applies flags that may appear anywhere on the command
+// line (--workspace NAME) and returns the remaining arguments
+func parseGlobalFlags(args []string) []string {
+	workspace = loadActiveWorkspace()
+
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--workspace" && i+1 < len(args):
+			i++
+			setWorkspace(args[i])
+		case strings.HasPrefix(arg, "--workspace="):
+			setWorkspace(strings.TrimPrefix(arg, "--workspace="))
+		case arg == "--config" && i+1 < len(args):
+			i++
+			configOverride = args[i]
+		case strings.HasPrefix(arg, "--config="):
+			configOverride = strings.TrimPrefix(arg, "--config=")
+		case arg == "--readonly":
+			readonlyFlag = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest
+}
+
+// setWorkspace switches the active workspace and remembers it for next launch
+func setWorkspace(name string) {
+	name = strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "default" {
+		name = ""
+	}
+	workspace = name
+
+	if home, err := os.UserHomeDir(); err == nil {
+		os.WriteFile(filepath.Join(home, ".todobi.workspace"), []byte(name+"\n"), 0644)
+	}
+}
+
+// loadActiveWorkspace returns the workspace persisted by setWorkspace
+func loadActiveWorkspace() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".todobi.workspace"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// listWorkspaces finds workspaces with a config file in the home directory
+func listWorkspaces() []string {
+	names := []string{""}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return names
+	}
+	matches, _ := filepath.Glob(filepath.Join(home, ".todobi-*.conf"))
+	for _, match := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), ".todobi-"), ".conf")
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// workspaceName is the display name of the active workspace
+func workspaceName() string {
+	if workspace == "" {
+		return "default"
+	}
+	return workspace
+}
+
+// Config operations
+func configPath() (string, error) {
+	if configOverride != "" {
+		return configOverride, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if workspace != "" {
+		return filepath.Join(home, ".todobi-"+workspace+".conf"), nil
+	}
+	return filepath.Join(home, configFileName), nil
+}
+
+// syncRepoName is the GitHub repo the active workspace syncs to
+func syncRepoName() string {
+	if workspace != "" {
+		return "todobi-sync-" + workspace
+	}
+	return "todobi-sync"
+}
+
+func loadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if stdioConfig() {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Saved configs are strict JSON, so only pay for relaxedJSON when a
+	// hand edit left comments or trailing commas behind
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		cfg = Config{}
+		data = relaxedJSON(data)
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	keepUnknownFields(&cfg, data)
+	migrateConfig(&cfg)
+
+	return &cfg, nil
+}
+
+// loadCLIConfig is loadConfig for one-shot commands, which print the
+// newer-config warning to stderr; the TUI shows it in the status bar
+func loadCLIConfig() (*Config, error) {
+	cfg, err := loadConfig()
+	if err == nil {
+		if warning := newerConfigWarning(cfg); warning != "" {
+			fmt.Fprintln(os.Stderr, "Warning: "+warning)
+		}
+	}
+	return cfg, err
+}
+
+// relaxedJSON strips // and /* */ comments and trailing commas so a
+// hand-annotated config still parses. Newlines are kept so error offsets
+// stay close; saveConfig always writes strict JSON.
+func relaxedJSON(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i < len(data) && !(data[i] == '*' && i+1 < len(data) && data[i+1] == '/') {
+				if data[i] == '\n' {
+					out = append(out, '\n')
+				}
+				i++
+			}
+			i++ // skip the closing slash
+		case c == '}' || c == ']':
+			// Drop a comma left dangling before the closer
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// unknownFields holds the JSON object members a struct has no field for,
+// so saving a config written by a newer todobi doesn't drop them
+type unknownFields map[string]json.RawMessage
+
+var (
+	taskFields     = jsonFieldNames(reflect.TypeOf(Task{}))
+	eventFields    = jsonFieldNames(reflect.TypeOf(TaskEvent{}))
+	categoryFields = jsonFieldNames(reflect.TypeOf(Category{}))
+	filterFields   = jsonFieldNames(reflect.TypeOf(Filter{}))
+	configFields   = jsonFieldNames(reflect.TypeOf(Config{}))
+)
+
+// jsonFieldNames lists the lowercased JSON names of a struct's fields
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		// encoding/json matches keys case-insensitively
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
+
+// keepUnknownFields holds on to the fields this build doesn't know, on the
+// config and on its categories, saved filters, tasks, and task events, so
+// saving stays lossless across todobi versions. A quick key scan finds
+// them; only objects that have some pay for a full parse.
+func keepUnknownFields(cfg *Config, data []byte) {
+	topLevel := false
+	forEachJSONMember(data, func(key, value []byte) {
+		switch {
+		case bytes.EqualFold(key, []byte("tasks")):
+			keepTaskUnknowns(cfg.Tasks, value)
+		case bytes.EqualFold(key, []byte("categories")):
+			forEachUnknown(value, categoryFields, func(i int, unknown unknownFields) {
+				if i < len(cfg.Categories) {
+					cfg.Categories[i].unknown = unknown
+				}
+			})
+		case bytes.EqualFold(key, []byte("saved_filters")):
+			forEachUnknown(value, filterFields, func(i int, unknown unknownFields) {
+				if i < len(cfg.SavedFilters) {
+					cfg.SavedFilters[i].unknown = unknown
+				}
+			})
+		default:
+			topLevel = topLevel || !knownField(key, configFields)
+		}
+	})
+	if topLevel {
+		cfg.unknown = splitUnknown(data, configFields)
+	}
+}
+
+// keepTaskUnknowns is keepUnknownFields for the tasks array, covering each
+// task's events in the same pass over its keys
+func keepTaskUnknowns(tasks []Task, data []byte) {
+	index := 0
+	forEachJSONElement(data, func(element []byte) {
+		if index >= len(tasks) {
+			return
+		}
+		task := &tasks[index]
+		index++
+		found := false
+		forEachJSONMember(element, func(key, value []byte) {
+			if !bytes.EqualFold(key, []byte("events")) {
+				found = found || !knownField(key, taskFields)
+				return
+			}
+			forEachUnknown(value, eventFields, func(i int, unknown unknownFields) {
+				if i < len(task.Events) {
+					task.Events[i].unknown = unknown
+				}
+			})
+		})
+		if found {
+			task.unknown = splitUnknown(element, taskFields)
+		}
+	})
+}
+
+// forEachUnknown calls fn with the index and unknown fields of each object
+// in a JSON array that has any
+func forEachUnknown(data []byte, known map[string]bool, fn func(i int, unknown unknownFields)) {
+	i := 0
+	forEachJSONElement(data, func(element []byte) {
+		if hasUnknown(element, known) {
+			fn(i, splitUnknown(element, known))
+		}
+		i++
+	})
+}
+
+// knownField reports whether a raw (still escaped) key names a known field;
+// escaped keys count as unknown and are sorted out by splitUnknown
+func knownField(key []byte, known map[string]bool) bool {
+	return known[string(key)] || known[strings.ToLower(string(key))]
+}
+
+// hasUnknown reports whether a JSON object has a key not in known
+func hasUnknown(data []byte, known map[string]bool) bool {
+	found := false
+	forEachJSONMember(data, func(key, _ []byte) {
+		found = found || !knownField(key, known)
+	})
+	return found
+}
+
+// forEachJSONMember calls fn with each raw key and value of a JSON object.
+// The data must be valid JSON; anything else ends the walk early.
+func forEachJSONMember(data []byte, fn func(key, value []byte)) {
+	i := skipJSONSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return
+	}
+	for i++; ; i++ {
+		i = skipJSONSpace(data, i)
+		if i >= len(data) || data[i] != '"' {
+			return
+		}
+		end := skipJSONString(data, i)
+		key := data[i+1 : end-1]
+		i = skipJSONSpace(data, skipJSONSpace(data, end)+1) // past the colon
+		valueEnd := skipJSONValue(data, i)
+		fn(key, data[i:valueEnd])
+		i = skipJSONSpace(data, valueEnd)
+		if i >= len(data) || data[i] != ',' {
+			return
+		}
+	}
+}
+
+// forEachJSONElement calls fn with each raw element of a JSON array
+func forEachJSONElement(data []byte, fn func(element []byte)) {
+	i := skipJSONSpace(data, 0)
+	if i >= len(data) || data[i] != '[' {
+		return
+	}
+	for i++; ; i++ {
+		i = skipJSONSpace(data, i)
+		if i >= len(data) || data[i] == ']' {
+			return
+		}
+		end := skipJSONValue(data, i)
+		fn(data[i:end])
+		i = skipJSONSpace(data, end)
+		if i >= len(data) || data[i] != ',' {
+			return
+		}
+	}
+}
+
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipJSONString returns the index just past the string opening at i
+func skipJSONString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// skipJSONValue returns the index just past the value starting at i
+func skipJSONValue(data []byte, i int) int {
+	depth := 0
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			i = skipJSONString(data, i) - 1
+			if depth == 0 {
+				return i + 1
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case ',', ' ', '\t', '\n', '\r':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// splitUnknown returns the members of a JSON object not in known
+func splitUnknown(data []byte, known map[string]bool) unknownFields {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil
+	}
+	for name := range members {
+		if known[strings.ToLower(name)] {
+			delete(members, name)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	return unknownFields(members)
+}
+
+// appendTo adds the fields to the end of a marshaled JSON object
+func (u unknownFields) appendTo(data []byte) []byte {
+	if len(u) == 0 {
+		return data
+	}
+	names := make([]string, 0, len(u))
+	for name := range u {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := data[:len(data)-1]
+	for _, name := range names {
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		key, _ := json.Marshal(name)
+		out = append(out, key...)
+		out = append(out, ':')
+		out = append(out, u[name]...)
+	}
+	return append(out, '}')
+}
+
+// parseRelease reads "1.4.2" or "v1.4.2-rc1" as major, minor, patch;
+// dev builds and empty strings don't parse
+func parseRelease(s string) ([3]int, bool) {
+	var parts [3]int
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	fields := strings.Split(s, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// releaseNewer reports whether release a is newer than b; anything that
+// doesn't parse counts as not newer
+func releaseNewer(a, b string) bool {
+	ra, okA := parseRelease(a)
+	rb, okB := parseRelease(b)
+	if !okA || !okB {
+		return false
+	}
+	for i := range ra {
+		if ra[i] != rb[i] {
+			return ra[i] > rb[i]
+		}
+	}
+	return false
+}
+
+// newerConfigWarning explains when a newer todobi last saved the config
+func newerConfigWarning(cfg *Config) string {
+	if !releaseNewer(cfg.WrittenBy, version) {
+		return ""
+	}
+	return fmt.Sprintf("config is newer (%s) than this todobi (%s) — some data may be lost on save",
+		cfg.WrittenBy, strings.TrimPrefix(version, "v"))
+}
+
+// stampWriter records this release in the config, never lowering what a
+// newer todobi recorded so the warning outlives an older binary's save
+func stampWriter(cfg *Config) {
+	if _, ok := parseRelease(version); !ok {
+		return
+	}
+	if _, ok := parseRelease(cfg.WrittenBy); ok && !releaseNewer(version, cfg.WrittenBy) {
+		return
+	}
+	cfg.WrittenBy = strings.TrimPrefix(version, "v")
+}
+
+// migrateConfig fills in fields added after a config was written
+func migrateConfig(cfg *Config) {
+	for i := range cfg.Tasks {
+		if link := cfg.Tasks[i].URL; link != "" {
+			if !slices.Contains(cfg.Tasks[i].URLs, link) {
+				cfg.Tasks[i].URLs = append([]string{link}, cfg.Tasks[i].URLs...)
+			}
+			cfg.Tasks[i].URL = ""
+		}
+		if cfg.Tasks[i].State == "" {
+			cfg.Tasks[i].State = cfg.Tasks[i].state()
+		}
+		if cfg.Tasks[i].Done && cfg.Tasks[i].TimesCompleted == 0 {
+			cfg.Tasks[i].TimesCompleted = 1
+		}
+	}
+}
+
+func saveConfig(cfg *Config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	cfg.LastUpdate = time.Now()
+	stampWriter(cfg)
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if stdioConfig() {
+		_, err := fmt.Fprintln(os.Stdout, string(data))
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// echoConfig hands an unchanged config back on stdout when it's being
+// piped, so a command with nothing to do doesn't swallow it
+func echoConfig(cfg *Config) error {
+	if !stdioConfig() {
+		return nil
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
+
+// errLockHeld is lockConfig's answer when another todobi holds the lock
+var errLockHeld = errors.New("the config is locked by another todobi")
+
+// lockConfig takes the lock file next to the active config without
+// waiting (tryLock is per platform); closing the file releases it. The
+// TUI holds it for the whole session, since its in-memory config would
+// overwrite anything saved underneath it, and `todobi serve` holds it
+// while it writes.
+func lockConfig() (*os.File, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := tryLock(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// sessionLock is lockConfig for the TUI, waiting out a write from serve.
+// A second TUI on the same config gets errLockHeld and runs without it.
+func sessionLock() (*os.File, error) {
+	lock, err := lockConfig()
+	for i := 0; i < 10 && errors.Is(err, errLockHeld); i++ {
+		time.Sleep(50 * time.Millisecond)
+		lock, err = lockConfig()
+	}
+	return lock, err
+}
+
+// lockStatus explains a session running without the config lock
+func lockStatus(err error) string {
+	if errors.Is(err, errLockHeld) {
+		return "⚠ Another todobi has this config open - saves here may overwrite its changes"
+	}
+	return "⚠ Couldn't lock the config: " + err.Error()
+}
+
+func (m *model) saveConfigAndMarkChanged() {
+	if err := saveConfig(m.config); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to save config: %v\n", err)
+		m.setStatus("Error saving: " + err.Error())
+		return
+	}
+	m.configChanged = true
+}
+
+// pruneCompleted removes completed tasks older than the configured
+// retention and returns how many were removed
+func pruneCompleted(cfg *Config, now time.Time) int {
+	if cfg.CompletedRetentionDays <= 0 {
+		return 0
+	}
+
+	cutoff := now.AddDate(0, 0, -cfg.CompletedRetentionDays)
+	kept := cfg.Tasks[:0]
+	pruned := 0
+	for _, task := range cfg.Tasks {
+		if task.Done && !task.CompletedAt.IsZero() && task.CompletedAt.Before(cutoff) {
+			pruned++
+			continue
+		}
+		kept = append(kept, task)
+	}
+	cfg.Tasks = kept
+	return pruned
+}
+
+// importInbox turns each non-blank line of the inbox file into a P1 task
+// in the Inbox category (created if missing). The file is first renamed
+// aside so lines appended mid-import start a fresh inbox instead of being
+// lost; it returns how many tasks were added and the renamed file, which
+// the caller deletes once the config is saved. A renamed file left by a
+// failed save is imported again before the inbox is touched.
+func importInbox(cfg *Config, now time.Time) (int, string, error) {
+	path := cfg.inboxPath()
+	if path == "" {
+		return 0, "", nil
+	}
+	claimed := path + ".importing"
+	if _, err := os.Stat(claimed); os.IsNotExist(err) {
+		info, err := os.Stat(path)
+		if err != nil || info.Size() == 0 {
+			return 0, "", nil
+		}
+		if err := os.Rename(path, claimed); err != nil {
+			return 0, "", err
+		}
+	}
+	data, err := os.ReadFile(claimed)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return 0, "", os.Remove(claimed)
+	}
+
+	inboxID := ""
+	for _, cat := range cfg.Categories {
+		if strings.EqualFold(cat.Name, "Inbox") {
+			inboxID = cat.ID
+			break
+		}
+	}
+	if inboxID == "" {
+		inboxID = generateID()
+		cfg.Categories = append(cfg.Categories, Category{ID: inboxID, Name: "Inbox"})
+	}
+
+	// generateID is clock based, so guard against repeats in a tight loop
+	used := map[string]bool{inboxID: true}
+	for _, task := range cfg.Tasks {
+		used[task.ID] = true
+	}
+	for _, line := range lines {
+		id := generateID()
+		for used[id] {
+			id = generateID()
+		}
+		used[id] = true
+		task := Task{
+			ID:         id,
+			Content:    line,
+			CategoryID: inboxID,
+			Priority:   P1High,
+			CreatedAt:  now,
+		}
+		task.logEvent("created", "from inbox", now)
+		cfg.Tasks = append(cfg.Tasks, task)
+	}
+	return len(lines), claimed, nil
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Version: "1.3.0",
+		Categories: []Category{
+			{ID: "work", Name: "Work"},
+			{ID: "personal", Name: "Personal"},
+		},
+		Tasks: []Task{
+			{
+				ID:         "1",
+				Content:    "Press 'C' to create category",
+				CategoryID: "work",
+				Priority:   P1High,
+				CreatedAt:  time.Now(),
+			},
+			{
+				ID:         "2",
+				Content:    "Press 'T' to create task",
+				CategoryID: "work",
+				Priority:   P2Medium,
+				CreatedAt:  time.Now(),
+			},
+			{
+				ID:         "3",
+				Content:    "Press 'v' to view completed tasks",
+				CategoryID: "personal",
+				Priority:   P3Low,
+				CreatedAt:  time.Now(),
+			},
+		},
+	}
+}
+
+func seedWeekendTasks() *Config {
+	return &Config{
+		Version: "1.3.0",
+		Categories: []Category{
+			{ID: "gummy-agents", Name: "Gummy Agents"},
+			{ID: "master-claude", Name: "Master Claude"},
+			{ID: "eldercare", Name: "Eldercare"},
+			{ID: "homelab", Name: "Homelab"},
+			{ID: "tailscale", Name: "File Sharing"},
+		},
+		Tasks: []Task{
+			{
+				ID:         "1",
+				Content:    "Pull down gummy-agents repo and review codebase",
+				CategoryID: "gummy-agents",
+				Priority:   P1High,
+				CreatedAt:  time.Now(),
+			},
+			{
+				ID:         "2",
+				Content:    "Review and organize master-claude-work projects",
+				CategoryID: "master-claude",
+				Priority:   P1High,
+				CreatedAt:  time.Now(),
+			},
+			{
 				ID:         "3",
-				Content:    "Press 'v' to view completed tasks",
-				CategoryID: "personal",
-				Priority:   P3Low,
+				Content:    "Address eldercare issues and documentation",
+				CategoryID: "eldercare",
+				Priority:   P0Critical,
+				CreatedAt:  time.Now(),
+			},
+			{
+				ID:         "4",
+				Content:    "Homelab infrastructure maintenance and updates",
+				CategoryID: "homelab",
+				Priority:   P2Medium,
+				CreatedAt:  time.Now(),
+			},
+			{
+				ID:         "5",
+				Content:    "Setup file sharing across tailscale network",
+				CategoryID: "tailscale",
+				Priority:   P2Medium,
 				CreatedAt:  time.Now(),
 			},
 		},
 	}
 }
 
-func seedWeekendTasks() *Config {
-	return &Config{
-		Version: "1.3.0",
-		Categories: []Category{
-			{ID: "gummy-agents", Name: "Gummy Agents"},
-			{ID: "master-claude", Name: "Master Claude"},
-			{ID: "eldercare", Name: "Eldercare"},
-			{ID: "homelab", Name: "Homelab"},
-			{ID: "tailscale", Name: "File Sharing"},
-		},
-		Tasks: []Task{
-			{
-				ID:         "1",
-				Content:    "Pull down gummy-agents repo and review codebase",
-				CategoryID: "gummy-agents",
-				Priority:   P1High,
-				CreatedAt:  time.Now(),
-			},
-			{
-				ID:         "2",
-				Content:    "Review and organize master-claude-work projects",
-				CategoryID: "master-claude",
-				Priority:   P1High,
-				CreatedAt:  time.Now(),
-			},
-			{
-				ID:         "3",
-				Content:    "Address eldercare issues and documentation",
-				CategoryID: "eldercare",
-				Priority:   P0Critical,
-				CreatedAt:  time.Now(),
-			},
-			{
-				ID:         "4",
-				Content:    "Homelab infrastructure maintenance and updates",
-				CategoryID: "homelab",
-				Priority:   P2Medium,
-				CreatedAt:  time.Now(),
-			},
-			{
-				ID:         "5",
-				Content:    "Setup file sharing across tailscale network",
-				CategoryID: "tailscale",
-				Priority:   P2Medium,
-				CreatedAt:  time.Now(),
-			},
-		},
+// Stats summarizes the task list for the stats command
+type Stats struct {
+	Total             int            `json:"total"`
+	Pending           int            `json:"pending"`
+	Completed         int            `json:"completed"`
+	Waiting           int            `json:"waiting"`
+	Backlog           int            `json:"backlog"`
+	ByPriority        map[string]int `json:"by_priority"`
+	ByCategory        map[string]int `json:"by_category"`
+	CompletionsPerDay map[string]int `json:"completions_per_day"`
+	AverageAgeDays    float64        `json:"average_age_days"`
+	AverageCycleDays  float64        `json:"average_cycle_days"`
+}
+
+// computeStats counts pending tasks by priority and category, completions
+// per day, the average age of pending tasks, and the average cycle time
+// (creation to completion) of completed ones. Backlog tasks are only counted.
+func computeStats(cfg *Config, now time.Time) Stats {
+	stats := Stats{
+		ByPriority:        make(map[string]int),
+		ByCategory:        make(map[string]int),
+		CompletionsPerDay: make(map[string]int),
+	}
+
+	categoryNames := make(map[string]string)
+	for _, cat := range cfg.Categories {
+		categoryNames[cat.ID] = cat.Name
+	}
+
+	var totalAge, totalCycle time.Duration
+	cycles := 0
+	for _, task := range cfg.Tasks {
+		stats.Total++
+		if task.Done {
+			stats.Completed++
+			if !task.CompletedAt.IsZero() {
+				stats.CompletionsPerDay[task.CompletedAt.Format("2006-01-02")]++
+			}
+			if d, ok := task.cycleTime(); ok {
+				totalCycle += d
+				cycles++
+			}
+			continue
+		}
+		if task.Backlog {
+			stats.Backlog++
+			continue
+		}
+
+		stats.Pending++
+		if task.state() == StateWaiting {
+			stats.Waiting++
+		}
+		stats.ByPriority[task.Priority.String()]++
+		name, ok := categoryNames[task.CategoryID]
+		if !ok {
+			name = "Unknown"
+		}
+		stats.ByCategory[name]++
+		totalAge += now.Sub(task.CreatedAt)
+	}
+
+	if stats.Pending > 0 {
+		stats.AverageAgeDays = math.Round(totalAge.Hours()/24/float64(stats.Pending)*10) / 10
+	}
+	if cycles > 0 {
+		stats.AverageCycleDays = math.Round(totalCycle.Hours()/24/float64(cycles)*10) / 10
+	}
+
+	return stats
+}
+
+// formatStats renders stats as plain text
+func formatStats(stats Stats) string {
+	var output strings.Builder
+
+	fmt.Fprintf(&output, "Tasks: %d total, %d pending (%d waiting), %d completed\n", stats.Total, stats.Pending, stats.Waiting, stats.Completed)
+	if stats.Backlog > 0 {
+		fmt.Fprintf(&output, "Backlog: %d someday/maybe\n", stats.Backlog)
+	}
+	fmt.Fprintf(&output, "Average pending age: %.1f days\n", stats.AverageAgeDays)
+	if stats.Completed > 0 {
+		fmt.Fprintf(&output, "Average cycle time: %.1f days\n", stats.AverageCycleDays)
+	}
+
+	output.WriteString("\nPending by priority:\n")
+	for _, p := range []Priority{P0Critical, P1High, P2Medium, P3Low} {
+		fmt.Fprintf(&output, "  %s  %d\n", p.String(), stats.ByPriority[p.String()])
+	}
+
+	output.WriteString("\nPending by category:\n")
+	for _, name := range sortedKeys(stats.ByCategory) {
+		fmt.Fprintf(&output, "  %-20s %d\n", name, stats.ByCategory[name])
+	}
+
+	output.WriteString("\nCompletions per day:\n")
+	for _, day := range sortedKeys(stats.CompletionsPerDay) {
+		fmt.Fprintf(&output, "  %s  %d\n", day, stats.CompletionsPerDay[day])
+	}
+
+	return output.String()
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// runStats implements `todobi stats [--json]`
+func runStats(args []string) error {
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	stats := computeStats(cfg, time.Now())
+
+	for _, arg := range args {
+		if arg == "--json" {
+			data, err := json.MarshalIndent(stats, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+	}
+
+	fmt.Print(formatStats(stats))
+	return nil
+}
+
+// validateConfig reports structural problems in a config, one line each
+func validateConfig(cfg *Config) []string {
+	var issues []string
+
+	if err := cfg.checkSyncVisibility(); err != nil {
+		issues = append(issues, err.Error())
+	}
+	switch cfg.groupBy() {
+	case "priority", "category":
+	default:
+		issues = append(issues, fmt.Sprintf("group_by %q: want priority or category", cfg.GroupBy))
+	}
+	if !slices.Contains(categoryDeleteModes, cfg.categoryDelete()) {
+		issues = append(issues, fmt.Sprintf("category_delete %q: want refuse, reassign, or delete", cfg.CategoryDelete))
+	}
+	if !slices.Contains(sortModes, cfg.sortMode()) {
+		issues = append(issues, fmt.Sprintf("sort_mode %q: want priority, created, name, or neglected", cfg.SortMode))
+	}
+	if _, _, err := progressStyleColors(cfg.ProgressStyle); err != nil {
+		issues = append(issues, fmt.Sprintf("progress_style %q: want accent, #rrggbb, or #rrggbb,#rrggbb", cfg.ProgressStyle))
+	}
+	if cfg.IdleLockMinutes < 0 {
+		issues = append(issues, fmt.Sprintf("idle_lock_minutes %d: want 0 (off) or more", cfg.IdleLockMinutes))
+	}
+	if cfg.DashboardPreviewCount != nil && *cfg.DashboardPreviewCount < 0 {
+		issues = append(issues, fmt.Sprintf("dashboard_preview_count %d: want 0 (all) or more", *cfg.DashboardPreviewCount))
+	}
+
+	categoryIDs := make(map[string]bool)
+	for i, cat := range cfg.Categories {
+		switch {
+		case cat.ID == "":
+			issues = append(issues, fmt.Sprintf("category #%d (%q): empty id", i+1, cat.Name))
+		case categoryIDs[cat.ID]:
+			issues = append(issues, fmt.Sprintf("category %s: duplicate id", cat.ID))
+		}
+		if strings.TrimSpace(cat.Name) == "" {
+			issues = append(issues, fmt.Sprintf("category %s: empty name", cat.ID))
+		}
+		categoryIDs[cat.ID] = true
+	}
+
+	taskIDs := make(map[string]bool)
+	for i, task := range cfg.Tasks {
+		label := fmt.Sprintf("task %s (%q)", task.ID, task.Content)
+		switch {
+		case task.ID == "":
+			label = fmt.Sprintf("task #%d (%q)", i+1, task.Content)
+			issues = append(issues, label+": empty id")
+		case taskIDs[task.ID]:
+			issues = append(issues, label+": duplicate id")
+		}
+		taskIDs[task.ID] = true
+
+		if strings.TrimSpace(task.Content) == "" {
+			issues = append(issues, label+": empty content")
+		}
+		if task.Priority < P0Critical || task.Priority > P3Low {
+			issues = append(issues, fmt.Sprintf("%s: invalid priority %d (want 0-3)", label, task.Priority))
+		}
+		if !categoryIDs[task.CategoryID] {
+			issues = append(issues, fmt.Sprintf("%s: unknown category %q", label, task.CategoryID))
+		}
+		if task.Done && task.CompletedAt.IsZero() {
+			issues = append(issues, label+": done but missing completed_at")
+		}
+		switch task.State {
+		case "", StateTodo, StateDoing, StateWaiting, StateDone:
+		default:
+			issues = append(issues, fmt.Sprintf("%s: unknown state %q", label, task.State))
+		}
+		for _, link := range task.URLs {
+			if u, err := url.Parse(link); err != nil || u.Scheme == "" || u.Host == "" {
+				issues = append(issues, fmt.Sprintf("%s: malformed url %q", label, link))
+			}
+		}
+	}
+
+	return issues
+}
+
+// runValidate checks the config and fails if validateConfig finds anything
+func runValidate() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading %s: %w", path, err)
+	}
+
+	issues := validateConfig(cfg)
+	if len(issues) == 0 {
+		fmt.Printf("PASS %s: %d tasks, %d categories\n", path, len(cfg.Tasks), len(cfg.Categories))
+		return nil
+	}
+
+	fmt.Printf("FAIL %s:\n", path)
+	for _, issue := range issues {
+		fmt.Printf("  - %s\n", issue)
+	}
+	return fmt.Errorf("%d problems found", len(issues))
+}
+
+// runBackup writes the live config to stdout byte for byte, so fields
+// this build doesn't know about survive a round trip. A hand-edited
+// config with comments is written as the strict JSON it parses to.
+func runBackup() error {
+	if stdioConfig() {
+		return fmt.Errorf("backup reads the live config; drop --config -")
+	}
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if !json.Valid(data) {
+		data = relaxedJSON(data)
+		if !json.Valid(data) {
+			return fmt.Errorf("%s is not valid JSON; fix it (see todobi validate) before backing up", path)
+		}
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// runRestore replaces the live config with a backup read from r. The
+// backup must load and pass validateConfig; it is then written as-is so
+// unknown fields are kept, and the old config is moved to <path>.bak.
+func runRestore(r io.Reader) error {
+	if stdioConfig() {
+		return fmt.Errorf("restore writes the live config; drop --config -")
+	}
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		data = relaxedJSON(data)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("backup is not a todobi config: %w", err)
+	}
+	migrateConfig(&cfg)
+	if issues := validateConfig(&cfg); len(issues) > 0 {
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "  - %s\n", issue)
+		}
+		return fmt.Errorf("backup has %d problems; nothing restored", len(issues))
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Rename(path, path+".bak"); err != nil {
+			return err
+		}
+		fmt.Printf("Previous config kept at %s.bak\n", path)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Restored %s: %d tasks, %d categories\n", path, len(cfg.Tasks), len(cfg.Categories))
+	return nil
+}
+
+// formatSize renders a byte count as B, KB, or MB
+func formatSize(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+}
+
+// runVersion implements `todobi version` and `todobi --version`: the build
+// version set through ldflags, then the config's schema when there is one
+func runVersion() {
+	release := version
+	if _, ok := parseRelease(version); ok {
+		release = "v" + strings.TrimPrefix(version, "v")
+	}
+	fmt.Printf("todobi %s (%s, %s)\n", release, commit, date)
+
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return
+	}
+	schema := cfg.Version
+	if schema == "" {
+		schema = "unset"
+	}
+	fmt.Printf("config schema %s", schema)
+	if cfg.WrittenBy != "" {
+		fmt.Printf(", last saved by todobi v%s", cfg.WrittenBy)
+	}
+	fmt.Println()
+}
+
+// runInfo implements `todobi path` (the config path alone, for scripts) and
+// `todobi info` (the path plus file metadata, counts, and versions)
+func runInfo(pathOnly bool) error {
+	if stdioConfig() {
+		return fmt.Errorf("--config - has no file to describe")
+	}
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if pathOnly {
+		fmt.Println(path)
+		return nil
+	}
+
+	fmt.Printf("Config:     %s\n", path)
+	fmt.Printf("Workspace:  %s\n", workspaceName())
+	fmt.Printf("todobi:     %s (%s, %s)\n", version, commit, date)
+
+	stat, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		fmt.Println("Status:     not created yet (launch todobi or run todobi --pull)")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	fmt.Printf("Size:       %s\n", formatSize(stat.Size()))
+	fmt.Printf("Modified:   %s\n", stat.ModTime().Format("2006-01-02 15:04"))
+
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading %s: %w", path, err)
+	}
+	done := 0
+	for _, task := range cfg.Tasks {
+		if task.Done {
+			done++
+		}
+	}
+	fmt.Printf("Tasks:      %d (%d pending, %d completed)\n", len(cfg.Tasks), len(cfg.Tasks)-done, done)
+	fmt.Printf("Categories: %d\n", len(cfg.Categories))
+	fmt.Printf("Schema:     %s\n", cfg.Version)
+	if cfg.WrittenBy != "" {
+		fmt.Printf("Written by: todobi %s\n", cfg.WrittenBy)
+	}
+
+	if cfg.GitHubSetupComplete {
+		fmt.Printf("Sync repo:  %s (%s)\n", cfg.syncRepoPath(), cfg.syncVisibility())
+	} else {
+		fmt.Println("Sync repo:  not set up")
+	}
+	return nil
+}
+
+// formatDigest summarizes pending tasks grouped by priority, as plain text
+// or as an HTML fragment suitable for mail
+func formatDigest(cfg *Config, now time.Time, asHTML bool) string {
+	categoryNames := make(map[string]string)
+	for _, cat := range cfg.Categories {
+		categoryNames[cat.ID] = cat.Name
+	}
+
+	groups := make(map[Priority][]Task)
+	pending := 0
+	for _, task := range cfg.Tasks {
+		if task.Done || task.Backlog || task.isSnoozed(now) {
+			continue
+		}
+		groups[task.Priority] = append(groups[task.Priority], task)
+		pending++
+	}
+
+	var output strings.Builder
+	title := fmt.Sprintf("todobi digest for %s: %d pending", now.Format("Mon Jan 2, 2006"), pending)
+	if asHTML {
+		fmt.Fprintf(&output, "<h2>%s</h2>\n", html.EscapeString(title))
+	} else {
+		output.WriteString(title + "\n")
+	}
+
+	for _, p := range []Priority{P0Critical, P1High, P2Medium, P3Low} {
+		tasks := groups[p]
+		if len(tasks) == 0 {
+			continue
+		}
+		sort.Slice(tasks, func(i, j int) bool {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		})
+
+		if asHTML {
+			fmt.Fprintf(&output, "<h3 style=\"color: %s\">%s (%d)</h3>\n<ul>\n", p.Color(), p.String(), len(tasks))
+		} else {
+			fmt.Fprintf(&output, "\n%s (%d)\n", p.String(), len(tasks))
+		}
+
+		for _, task := range tasks {
+			category := categoryNames[task.CategoryID]
+			days := int(now.Sub(task.CreatedAt).Hours() / 24)
+			if asHTML {
+				fmt.Fprintf(&output, "  <li>%s <em>[%s]</em> &middot; %dd old</li>\n",
+					html.EscapeString(task.Content), html.EscapeString(category), days)
+			} else {
+				fmt.Fprintf(&output, "  - %s [%s] (%dd old)\n", task.Content, category, days)
+			}
+		}
+
+		if asHTML {
+			output.WriteString("</ul>\n")
+		}
+	}
+
+	return output.String()
+}
+
+// githubRef shortens a GitHub issue or pull request URL to owner/repo#N,
+// which GitHub links wherever it's pasted; ok is false for other URLs
+func githubRef(raw string) (ref string, ok bool) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Host != "github.com" && u.Host != "www.github.com") {
+		return "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 || (parts[2] != "issues" && parts[2] != "pull") {
+		return "", false
+	}
+	if _, err := strconv.Atoi(parts[3]); err != nil {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1] + "#" + parts[3], true
+}
+
+// formatMarkdown writes pending tasks as a GFM task list grouped by
+// priority. With github set, issue and PR links become owner/repo#N
+// references so a pasted checklist tracks them.
+func formatMarkdown(cfg *Config, github bool) string {
+	groups := make(map[Priority][]Task)
+	for _, task := range cfg.Tasks {
+		if !task.Done {
+			groups[task.Priority] = append(groups[task.Priority], task)
+		}
+	}
+
+	var output strings.Builder
+	for _, p := range []Priority{P0Critical, P1High, P2Medium, P3Low} {
+		tasks := groups[p]
+		if len(tasks) == 0 {
+			continue
+		}
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return tasks[i].Rank < tasks[j].Rank
+		})
+
+		if output.Len() > 0 {
+			output.WriteString("\n")
+		}
+		fmt.Fprintf(&output, "### %s %s (%d)\n\n", p.String(), p.Label(), len(tasks))
+		for _, task := range tasks {
+			line := fmt.Sprintf("- [ ] %s _(%s)_", task.Content, cfg.categoryName(task.CategoryID))
+			for _, link := range task.URLs {
+				if ref, ok := githubRef(link); ok && github {
+					line += " " + ref
+				} else {
+					line += fmt.Sprintf(" [link](%s)", link)
+				}
+			}
+			output.WriteString(line + "\n")
+		}
+	}
+
+	// Category scratchpads, after the tasks
+	for _, cat := range cfg.Categories {
+		if cat.Notes == "" {
+			continue
+		}
+		if output.Len() > 0 {
+			output.WriteString("\n")
+		}
+		fmt.Fprintf(&output, "### %s notes\n\n%s\n", cat.Name, cat.Notes)
+	}
+	return output.String()
+}
+
+// runExport implements `todobi export [--format markdown|github]`
+func runExport(args []string) error {
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	format := "markdown"
+	for i := 0; i < len(args); i++ {
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		if flag != "--format" {
+			return fmt.Errorf("unknown flag %q (usage: todobi export [--format markdown|github])", args[i])
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", flag)
+			}
+			i++
+			value = args[i]
+		}
+		format = value
+	}
+
+	switch format {
+	case "markdown", "md":
+		fmt.Print(formatMarkdown(cfg, false))
+	case "github", "gfm":
+		fmt.Print(formatMarkdown(cfg, true))
+	default:
+		return fmt.Errorf("unknown format %q (want markdown or github)", format)
+	}
+	return nil
+}
+
+// runDigest implements `todobi digest [--html]`
+func runDigest(args []string) error {
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	asHTML := false
+	for _, arg := range args {
+		if arg == "--html" {
+			asHTML = true
+		}
+	}
+
+	fmt.Print(formatDigest(cfg, time.Now(), asHTML))
+	return nil
+}
+
+// parseSinceAt reads a --since value: a bare offset counts back from today
+// (7d, 2w, -7d), anything else goes through parseDateInputAt
+func parseSinceAt(s string, now time.Time) (time.Time, error) {
+	input := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "-")
+	if input == "yesterday" {
+		return startOfDay(now).AddDate(0, 0, -1), nil
+	}
+	// +3d is a forward offset, which parseDateInputAt handles
+	if len(input) > 1 && input[0] != '+' {
+		var n int
+		unit := input[len(input)-1]
+		if _, err := fmt.Sscanf(input[:len(input)-1], "%d", &n); err == nil && n >= 0 {
+			switch unit {
+			case 'd':
+				return startOfDay(now).AddDate(0, 0, -n), nil
+			case 'w':
+				return startOfDay(now).AddDate(0, 0, -7*n), nil
+			}
+		}
+	}
+	return parseDateInputAt(s, now)
+}
+
+// formatLog lists tasks completed on or after since, grouped by completion
+// day with the most recent first. An empty categoryID means all categories.
+func formatLog(cfg *Config, since time.Time, categoryID string) string {
+	days := make(map[time.Time][]Task)
+	total := 0
+	for _, task := range cfg.Tasks {
+		if !task.Done || task.CompletedAt.Before(since) {
+			continue
+		}
+		if categoryID != "" && task.CategoryID != categoryID {
+			continue
+		}
+		day := startOfDay(task.CompletedAt.Local())
+		days[day] = append(days[day], task)
+		total++
+	}
+
+	var order []time.Time
+	for day := range days {
+		order = append(order, day)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return order[i].After(order[j])
+	})
+
+	var output strings.Builder
+	fmt.Fprintf(&output, "Completed since %s: %d tasks\n", since.Format("Mon Jan 2, 2006"), total)
+
+	for _, day := range order {
+		tasks := days[day]
+		sort.Slice(tasks, func(i, j int) bool {
+			return tasks[i].CompletedAt.Before(tasks[j].CompletedAt)
+		})
+
+		fmt.Fprintf(&output, "\n%s (%d)\n", day.Format("Mon Jan 2, 2006"), len(tasks))
+		for _, task := range tasks {
+			fmt.Fprintf(&output, "  - %s [%s] %s\n", task.Content, cfg.categoryName(task.CategoryID), task.Priority)
+		}
+	}
+
+	return output.String()
+}
+
+// runLog implements `todobi log [--since 7d] [--category NAME]`
+func runLog(args []string) error {
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	now := time.Now()
+	since := startOfDay(now).AddDate(0, 0, -7)
+	categoryID := ""
+	for i := 0; i < len(args); i++ {
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		if flag != "--since" && flag != "--category" {
+			return fmt.Errorf("unknown flag %q (usage: todobi log [--since 7d] [--category NAME])", args[i])
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", flag)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch flag {
+		case "--since":
+			if since, err = parseSinceAt(value, now); err != nil {
+				return err
+			}
+		case "--category":
+			for _, cat := range cfg.Categories {
+				if strings.EqualFold(cat.Name, value) || cat.ID == value {
+					categoryID = cat.ID
+				}
+			}
+			if categoryID == "" {
+				return fmt.Errorf("no category named %q", value)
+			}
+		}
+	}
+
+	fmt.Print(formatLog(cfg, since, categoryID))
+	return nil
+}
+
+// formatPrompt fills the prompt_format placeholders; done/total follow the
+// progress bar, the rest count pending tasks that aren't snoozed or in
+// the backlog
+func formatPrompt(cfg *Config, now time.Time) string {
+	done, total := cfg.progress()
+	pending, p0, doing, waiting, snoozed := 0, 0, 0, 0, 0
+	for _, task := range cfg.Tasks {
+		if task.Done || task.Backlog {
+			continue
+		}
+		if task.isSnoozed(now) {
+			snoozed++
+			continue
+		}
+		pending++
+		if task.Priority == P0Critical {
+			p0++
+		}
+		switch task.state() {
+		case StateDoing:
+			doing++
+		case StateWaiting:
+			waiting++
+		}
+	}
+
+	return strings.NewReplacer(
+		"{done}", strconv.Itoa(done),
+		"{total}", strconv.Itoa(total),
+		"{pending}", strconv.Itoa(pending),
+		"{p0}", strconv.Itoa(p0),
+		"{doing}", strconv.Itoa(doing),
+		"{waiting}", strconv.Itoa(waiting),
+		"{snoozed}", strconv.Itoa(snoozed),
+		"{today}", strconv.Itoa(cfg.completedOn(now)),
+	).Replace(cfg.promptFormat())
+}
+
+// runPrompt implements `todobi prompt`: it only reads the local config, so
+// it never waits on the network, and prints nothing when that fails
+func runPrompt() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatPrompt(cfg, time.Now()))
+	return nil
+}
+
+// runComplete implements the hidden `todobi __complete categories|tasks|priorities`,
+// printing one candidate per line for the completion scripts
+func runComplete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: todobi __complete categories|tasks|priorities")
+	}
+
+	switch args[0] {
+	case "priorities":
+		for p := P0Critical; p <= P3Low; p++ {
+			fmt.Println(p)
+		}
+		return nil
+	case "categories", "tasks":
+	default:
+		return fmt.Errorf("unknown completion %q", args[0])
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if args[0] == "categories" {
+		for _, cat := range cfg.Categories {
+			fmt.Println(cat.Name)
+		}
+		return nil
+	}
+	for _, task := range cfg.Tasks {
+		fmt.Println(task.ID)
+	}
+	return nil
+}
+
+// cliCommands are the subcommands the completion scripts offer
+var cliCommands = []struct {
+	Name string
+	Help string
+}{
+	{"list", "Pending tasks, one per line"},
+	{"pick", "Fuzzy-pick a pending task, print its ID"},
+	{"log", "Completed tasks by day"},
+	{"prompt", "One-line summary for a shell prompt"},
+	{"stats", "Task statistics"},
+	{"digest", "Daily digest"},
+	{"export", "Markdown task list"},
+	{"sync-issues", "Complete tasks whose GitHub issue closed"},
+	{"serve", "HTTP+JSON API over the config"},
+	{"dedupe", "Remove tasks with identical content"},
+	{"validate", "Check the config for problems"},
+	{"path", "Print the config path"},
+	{"info", "Config path, size, and build details"},
+	{"backup", "Print the full config"},
+	{"restore", "Replace the config from stdin"},
+	{"seed", "Replace the config with sample tasks"},
+	{"completion", "Shell completion script"},
+	{"version", "Build and config schema versions"},
+}
+
+// runCompletion implements `todobi completion bash|zsh|fish`. The scripts
+// are static apart from the command list; categories come from
+// `todobi __complete` at completion time.
+func runCompletion(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: todobi completion bash|zsh|fish")
+	}
+
+	var script strings.Builder
+	switch args[0] {
+	case "bash":
+		var names []string
+		for _, c := range cliCommands {
+			names = append(names, c.Name)
+		}
+		script.WriteString(strings.Replace(bashCompletion, "{commands}", strings.Join(names, " "), 1))
+	case "zsh":
+		var commands strings.Builder
+		for _, c := range cliCommands {
+			fmt.Fprintf(&commands, "    '%s:%s'\n", c.Name, c.Help)
+		}
+		script.WriteString(strings.Replace(zshCompletion, "{commands}", commands.String(), 1))
+	case "fish":
+		var names []string
+		for _, c := range cliCommands {
+			names = append(names, c.Name)
+		}
+		script.WriteString(strings.Replace(fishCompletion, "{commands}", strings.Join(names, " "), 1))
+		for _, c := range cliCommands {
+			fmt.Fprintf(&script, "complete -c todobi -n \"not __fish_seen_subcommand_from $commands\" -a %s -d '%s'\n", c.Name, c.Help)
+		}
+	default:
+		return fmt.Errorf("unknown shell %q (want bash, zsh, or fish)", args[0])
+	}
+
+	fmt.Print(script.String())
+	return nil
+}
+
+const bashCompletion = `# bash completion for todobi: source <(todobi completion bash)
+_todobi() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local cmd="" i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        case "${COMP_WORDS[i]}" in
+            --workspace|--config) ((i++)) ;;
+            -*) ;;
+            *) cmd="${COMP_WORDS[i]}"; break ;;
+        esac
+    done
+
+    case "$prev" in
+        --category)
+            local IFS=$'\n'
+            COMPREPLY=($(compgen -W "$(todobi __complete categories 2>/dev/null)" -- "$cur"))
+            return ;;
+        --format)
+            COMPREPLY=($(compgen -W "markdown github" -- "$cur"))
+            return ;;
+        --print)
+            COMPREPLY=($(compgen -W "id content url" -- "$cur"))
+            return ;;
+        --config)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return ;;
+        --workspace|--since|--addr)
+            return ;;
+    esac
+
+    local words
+    case "$cmd" in
+        "") words="{commands} --workspace --config --readonly --pull --version" ;;
+        stats) words="--json" ;;
+        digest) words="--html" ;;
+        export) words="--format" ;;
+        log) words="--since --category" ;;
+        dedupe) words="--yes" ;;
+        sync-issues) words="--reopen --yes" ;;
+        serve) words="--addr" ;;
+        pick) words="--print" ;;
+        completion) words="bash zsh fish" ;;
+    esac
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -F _todobi todobi
+`
+
+const zshCompletion = `#compdef todobi
+# zsh completion for todobi: todobi completion zsh > "${fpath[1]}/_todobi"
+
+_todobi_categories() {
+  local -a categories
+  categories=("${(@f)$(todobi __complete categories 2>/dev/null)}")
+  compadd -a categories
+}
+
+_todobi() {
+  local -a commands
+  commands=(
+{commands}  )
+
+  _arguments -C \
+    '--workspace[use a named workspace]:workspace:' \
+    '--config[config file, or - for stdin]:config file:_files' \
+    '--readonly[browse without saving]' \
+    '--pull[pull the config from GitHub]' \
+    '--version[print the version]' \
+    '1:command:->command' \
+    '*::arg:->args'
+
+  case $state in
+    command)
+      _describe 'command' commands ;;
+    args)
+      case $words[1] in
+        stats) _arguments '--json[print JSON]' ;;
+        digest) _arguments '--html[print HTML]' ;;
+        export) _arguments '--format[output format]:format:(markdown github)' ;;
+        log) _arguments '--since[how far back, e.g. 7d]:since:' '--category[only this category]:category:_todobi_categories' ;;
+        dedupe) _arguments '--yes[skip the confirmation]' ;;
+        sync-issues) _arguments '--reopen[reopen tasks whose issue reopened]' '--yes[skip the confirmation]' ;;
+        serve) _arguments '--addr[listen address]:host\:port:' ;;
+        pick) _arguments '--print[field to print]:field:(id content url)' '*:query:' ;;
+        completion) _values 'shell' bash zsh fish ;;
+      esac ;;
+  esac
+}
+
+_todobi "$@"
+`
+
+const fishCompletion = `# fish completion for todobi: todobi completion fish > ~/.config/fish/completions/todobi.fish
+set -l commands {commands}
+complete -c todobi -f
+complete -c todobi -l workspace -x -d 'Use a named workspace'
+complete -c todobi -l config -r -F -d 'Config file, or - for stdin'
+complete -c todobi -l readonly -d 'Browse without saving'
+complete -c todobi -n "not __fish_seen_subcommand_from $commands" -l pull -d 'Pull the config from GitHub'
+complete -c todobi -n "not __fish_seen_subcommand_from $commands" -l version -d 'Print the version'
+complete -c todobi -n "__fish_seen_subcommand_from stats" -l json -d 'Print JSON'
+complete -c todobi -n "__fish_seen_subcommand_from digest" -l html -d 'Print HTML'
+complete -c todobi -n "__fish_seen_subcommand_from export" -l format -x -a 'markdown github' -d 'Output format'
+complete -c todobi -n "__fish_seen_subcommand_from log" -l since -x -d 'How far back, e.g. 7d'
+complete -c todobi -n "__fish_seen_subcommand_from log" -l category -x -a '(todobi __complete categories 2>/dev/null)' -d 'Only this category'
+complete -c todobi -n "__fish_seen_subcommand_from dedupe sync-issues" -l yes -d 'Skip the confirmation'
+complete -c todobi -n "__fish_seen_subcommand_from sync-issues" -l reopen -d 'Reopen tasks whose issue reopened'
+complete -c todobi -n "__fish_seen_subcommand_from serve" -l addr -x -d 'Listen address (host:port)'
+complete -c todobi -n "__fish_seen_subcommand_from pick" -l print -x -a 'id content url' -d 'Field to print'
+complete -c todobi -n "__fish_seen_subcommand_from completion" -a 'bash zsh fish'
+`
+
+// errPickCancelled is returned when the picker is closed without a choice
+var errPickCancelled = errors.New("nothing picked")
+
+// pickModel is the compact picker behind `todobi pick`: a query line over
+// the pending tasks, ranked by the same fuzzy match the list filter uses
+type pickModel struct {
+	input   textinput.Model
+	tasks   []TaskItem
+	matches []list.Rank
+	cursor  int
+	height  int
+	chosen  *TaskItem
+	done    bool
+}
+
+// maxPickRows bounds how many matches the picker shows at once
+const maxPickRows = 10
+
+func (m pickModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m pickModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			m.done = true
+			return m, tea.Quit
+		case "enter":
+			if m.cursor < len(m.matches) {
+				m.chosen = &m.tasks[m.matches[m.cursor].Index]
+			}
+			m.done = true
+			return m, tea.Quit
+		case "up", "ctrl+p", "ctrl+k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "ctrl+n", "ctrl+j":
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	before := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != before {
+		m.match()
+	}
+	return m, cmd
+}
+
+// match ranks the tasks against the query; an empty query keeps list order
+func (m *pickModel) match() {
+	m.cursor = 0
+	query := strings.TrimSpace(m.input.Value())
+	if query == "" {
+		m.matches = make([]list.Rank, len(m.tasks))
+		for i := range m.tasks {
+			m.matches[i] = list.Rank{Index: i}
+		}
+		return
+	}
+	targets := make([]string, len(m.tasks))
+	for i, task := range m.tasks {
+		targets[i] = task.Content
+	}
+	m.matches = list.DefaultFilter(query, targets)
+}
+
+func (m pickModel) View() string {
+	if m.done {
+		return ""
+	}
+
+	rows := maxPickRows
+	if m.height > 0 {
+		rows = min(rows, m.height-2)
+	}
+	start := 0
+	if m.cursor >= rows {
+		start = m.cursor - rows + 1
+	}
+
+	var output strings.Builder
+	output.WriteString(m.input.View())
+	output.WriteString(lipgloss.NewStyle().Foreground(colorMuted).Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.tasks))))
+	output.WriteString("\n")
+
+	matchStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true)
+	plainStyle := lipgloss.NewStyle()
+	for i := start; i < len(m.matches) && i < start+rows; i++ {
+		task := m.tasks[m.matches[i].Index]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = matchStyle.Render("> ")
+		}
+		content := lipgloss.StyleRunes(task.Content, m.matches[i].MatchedIndexes, matchStyle, plainStyle)
+		priority := lipgloss.NewStyle().Foreground(lipgloss.Color(task.Priority.Color())).Render(task.Priority.String())
+		category := lipgloss.NewStyle().Foreground(categoryColor(task.CategoryColor)).Render("[" + task.CategoryName + "]")
+		output.WriteString(fmt.Sprintf("%s%s %s %s\n", cursor, priority, content, category))
+	}
+	return output.String()
+}
+
+// runPick implements `todobi pick [--print id|content|url] [query...]`: a
+// one-shot picker over pending tasks that prints the chosen task's field.
+// It draws on stderr so stdout carries only the answer.
+func runPick(args []string) error {
+	field := "id"
+	var query []string
+	for i := 0; i < len(args); i++ {
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		if !strings.HasPrefix(flag, "--") {
+			query = append(query, args[i])
+			continue
+		}
+		if flag != "--print" {
+			return fmt.Errorf("unknown flag %q (usage: todobi pick [--print id|content|url] [query])", args[i])
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", flag)
+			}
+			i++
+			value = args[i]
+		}
+		switch value {
+		case "id", "content", "url":
+			field = value
+		default:
+			return fmt.Errorf("unknown field %q (want id, content, or url)", value)
+		}
+	}
+	if stdioConfig() {
+		return fmt.Errorf("pick reads keys from stdin, so it can't also read the config there")
+	}
+
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	var tasks []TaskItem
+	for _, task := range cfg.Tasks {
+		if task.Done {
+			continue
+		}
+		item := TaskItem{Task: task, CategoryName: cfg.categoryName(task.CategoryID)}
+		for _, cat := range cfg.Categories {
+			if cat.ID == task.CategoryID {
+				item.CategoryColor = cat.Color
+			}
+		}
+		tasks = append(tasks, item)
+	}
+	if len(tasks) == 0 {
+		return fmt.Errorf("no pending tasks to pick from")
+	}
+	less := cfg.sorted(activeLess)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return less(tasks[i], tasks[j])
+	})
+
+	// Colors follow the terminal we draw on, not the captured stdout
+	lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(os.Stderr))
+	input := textinput.New()
+	input.Prompt = "pick> "
+	input.Placeholder = "type to filter"
+	input.SetValue(strings.Join(query, " "))
+	input.CursorEnd()
+	input.Focus()
+	m := pickModel{input: input, tasks: tasks}
+	m.match()
+
+	result, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return err
+	}
+	chosen := result.(pickModel).chosen
+	if chosen == nil {
+		return errPickCancelled
+	}
+
+	switch field {
+	case "content":
+		fmt.Println(chosen.Content)
+	case "url":
+		if len(chosen.URLs) == 0 {
+			return fmt.Errorf("%q has no URL", chosen.Content)
+		}
+		for _, link := range chosen.URLs {
+			fmt.Println(link)
+		}
+	default:
+		fmt.Println(chosen.ID)
+	}
+	return nil
+}
+
+// runList prints pending tasks one per line, ordered like the TUI list
+func runList() error {
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	var items []TaskItem
+	for _, task := range cfg.Tasks {
+		if !task.Done && !task.Backlog {
+			items = append(items, TaskItem{Task: task, CategoryName: cfg.categoryName(task.CategoryID)})
+		}
+	}
+	less := cfg.sorted(activeLess)
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(items[i], items[j])
+	})
+
+	for _, item := range items {
+		fmt.Printf("%s  %-14s %s\n", item.Priority, item.CategoryName, item.Content)
+	}
+	return nil
+}
+
+// normalizeContent folds case and whitespace for duplicate detection
+func normalizeContent(content string) string {
+	return strings.Join(strings.Fields(strings.ToLower(content)), " ")
+}
+
+// findDuplicates groups tasks with identical normalized content. Each group
+// is ordered with the task worth keeping first: done over pending, with
+// notes over without, with links over without, then the oldest.
+func findDuplicates(cfg *Config) [][]Task {
+	groups := make(map[string][]Task)
+	var order []string
+	for _, task := range cfg.Tasks {
+		key := normalizeContent(task.Content)
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], task)
+	}
+
+	var duplicates [][]Task
+	for _, key := range order {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Done != group[j].Done {
+				return group[i].Done
+			}
+			if (group[i].Notes != "") != (group[j].Notes != "") {
+				return group[i].Notes != ""
+			}
+			if (len(group[i].URLs) > 0) != (len(group[j].URLs) > 0) {
+				return len(group[i].URLs) > 0
+			}
+			return group[i].CreatedAt.Before(group[j].CreatedAt)
+		})
+		duplicates = append(duplicates, group)
+	}
+
+	return duplicates
+}
+
+// removeDuplicates deletes every task but the first of each group and
+// returns how many were removed
+func removeDuplicates(cfg *Config, duplicates [][]Task) int {
+	remove := make(map[string]bool)
+	for _, group := range duplicates {
+		for _, task := range group[1:] {
+			remove[task.ID] = true
+		}
+	}
+
+	kept := cfg.Tasks[:0]
+	for _, task := range cfg.Tasks {
+		if !remove[task.ID] {
+			kept = append(kept, task)
+		}
+	}
+	removed := len(cfg.Tasks) - len(kept)
+	cfg.Tasks = kept
+	return removed
+}
+
+// githubIssueState asks gh whether an issue is OPEN or CLOSED
+func githubIssueState(issueURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "gh", "issue", "view", issueURL, "--json", "state").Output()
+	if err != nil {
+		if details := strings.TrimSpace(stderrOf(err)); details != "" {
+			return "", fmt.Errorf("%s", details)
+		}
+		return "", err
+	}
+	var issue struct {
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(output, &issue); err != nil {
+		return "", err
+	}
+	return issue.State, nil
+}
+
+// runSyncIssues implements `todobi sync-issues [--reopen] [--yes]`: tasks
+// linked to a closed GitHub issue are completed, and with --reopen, done
+// tasks whose issue was reopened go back to todo. The batch is listed and
+// confirmed before anything changes; other URLs are skipped.
+func runSyncIssues(args []string) error {
+	reopen, confirmed := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--reopen":
+			reopen = true
+		case "--yes", "-y":
+			confirmed = true
+		default:
+			return fmt.Errorf("unknown flag %q (usage: todobi sync-issues [--reopen] [--yes])", arg)
+		}
+	}
+
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	if err := exec.Command("gh", "--version").Run(); err != nil {
+		return fmt.Errorf("gh CLI not found - install it from https://cli.github.com")
+	}
+
+	out := infoOut()
+	changes := make(map[string]TaskState)
+	for _, task := range cfg.Tasks {
+		// The first issue link is the one the task tracks
+		var issue, ref string
+		for _, link := range task.URLs {
+			if r, ok := githubRef(link); ok && strings.Contains(link, "/issues/") {
+				issue, ref = link, r
+				break
+			}
+		}
+		if issue == "" {
+			continue
+		}
+		if task.Done && !reopen {
+			continue
+		}
+
+		state, err := githubIssueState(issue)
+		if err != nil {
+			fmt.Fprintf(out, "Skip:     %s (%s): %v\n", task.Content, ref, err)
+			continue
+		}
+		switch {
+		case state == "CLOSED" && !task.Done:
+			fmt.Fprintf(out, "Complete: %s (%s closed)\n", task.Content, ref)
+			changes[task.ID] = StateDone
+		case state == "OPEN" && task.Done:
+			fmt.Fprintf(out, "Reopen:   %s (%s reopened)\n", task.Content, ref)
+			changes[task.ID] = StateTodo
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "Every linked task already matches its issue.")
+		return echoConfig(cfg)
+	}
+	if !confirmed && stdioConfig() {
+		return fmt.Errorf("stdin holds the config, so sync-issues can't ask; pass --yes")
+	}
+	if !confirmed {
+		fmt.Fprintf(out, "Apply %d changes? [y/N] ", len(changes))
+		var answer string
+		fmt.Scanln(&answer)
+		confirmed = strings.EqualFold(strings.TrimSpace(answer), "y")
+	}
+	if !confirmed {
+		fmt.Fprintln(out, "Nothing changed.")
+		return nil
+	}
+
+	now := time.Now()
+	for i := range cfg.Tasks {
+		if state, ok := changes[cfg.Tasks[i].ID]; ok {
+			cfg.Tasks[i].setState(state, now)
+		}
+	}
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("error saving config: %w", err)
+	}
+	fmt.Fprintf(out, "Updated %s.\n", taskCount(len(changes)))
+	return nil
+}
+
+// apiServer serves `todobi serve`. Each request reloads the config so
+// edits from the TUI or CLI show up, and mu keeps this server's own
+// read-modify-write cycles from interleaving. Writes also take the config
+// lock, so they're refused while a TUI has the config open.
+type apiServer struct {
+	mu       sync.Mutex
+	readonly bool
+}
+
+// taskPatch is the PATCH /tasks/{id} body; absent fields stay as they are
+type taskPatch struct {
+	Content    *string    `json:"content"`
+	Priority   *Priority  `json:"priority"`
+	CategoryID *string    `json:"category_id"`
+	URLs       *[]string  `json:"urls"`
+	Notes      *string    `json:"notes"`
+	State      *TaskState `json:"state"`
+}
+
+// runServe implements `todobi serve [--addr host:port]`: GET /tasks,
+// POST /tasks, PATCH /tasks/{id}, and GET /stats. There is no
+// authentication, so the default address only listens locally.
+func runServe(args []string) error {
+	addr := "localhost:8080"
+	for i := 0; i < len(args); i++ {
+		flag, value, hasValue := strings.Cut(args[i], "=")
+		if flag != "--addr" {
+			return fmt.Errorf("unknown flag %q (usage: todobi serve [--addr host:port])", args[i])
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s needs a value", flag)
+			}
+			i++
+			value = args[i]
+		}
+		addr = value
+	}
+	if stdioConfig() {
+		return fmt.Errorf("--config - can't be served; point --config at a file")
+	}
+	if _, err := loadCLIConfig(); err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	api := &apiServer{readonly: readonlyFlag}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", api.listTasks)
+	mux.HandleFunc("POST /tasks", api.createTask)
+	mux.HandleFunc("PATCH /tasks/{id}", api.updateTask)
+	mux.HandleFunc("GET /stats", api.stats)
+
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	mode := "read/write"
+	if api.readonly {
+		mode = "read-only"
+	}
+	fmt.Fprintf(os.Stderr, "Serving %s (%s) on http://%s\n", workspaceName(), mode, addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// writeJSON sends v as the response body with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError sends {"error": msg}
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// readBody decodes a JSON request body, rejecting unknown fields so a
+// misspelled one doesn't silently do nothing
+func readBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// load reads the config for a request, answering with an error if it can't
+func (s *apiServer) load(w http.ResponseWriter) (*Config, bool) {
+	cfg, err := loadConfig()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error loading config: "+err.Error())
+		return nil, false
+	}
+	return cfg, true
+}
+
+// lock takes the config lock for a write, answering 409 while the TUI
+// holds it since its next save would drop the change
+func (s *apiServer) lock(w http.ResponseWriter) (*os.File, bool) {
+	lock, err := lockConfig()
+	if errors.Is(err, errLockHeld) {
+		writeError(w, http.StatusConflict, "todobi is open on this config; quit it to write through the API")
+		return nil, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "error locking config: "+err.Error())
+		return nil, false
+	}
+	return lock, true
+}
+
+// writable refuses changes when serving with --readonly
+func (s *apiServer) writable(w http.ResponseWriter) bool {
+	if s.readonly {
+		writeError(w, http.StatusForbidden, "server is read-only")
+		return false
+	}
+	return true
+}
+
+func (s *apiServer) listTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, ok := s.load(w)
+	if !ok {
+		return
+	}
+	tasks := cfg.Tasks
+	if tasks == nil {
+		tasks = []Task{}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *apiServer) stats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, ok := s.load(w)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, computeStats(cfg, time.Now()))
+}
+
+func (s *apiServer) createTask(w http.ResponseWriter, r *http.Request) {
+	if !s.writable(w) {
+		return
+	}
+	var body struct {
+		Content    string    `json:"content"`
+		Priority   *Priority `json:"priority"`
+		CategoryID string    `json:"category_id"`
+		URLs       []string  `json:"urls"`
+		Notes      string    `json:"notes"`
+	}
+	if !readBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.lock(w)
+	if !ok {
+		return
+	}
+	defer lock.Close()
+	cfg, ok := s.load(w)
+	if !ok {
+		return
+	}
+
+	task := Task{
+		ID:         generateID(),
+		Content:    strings.TrimSpace(body.Content),
+		CategoryID: body.CategoryID,
+		Priority:   P1High,
+		URLs:       cleanURLs(body.URLs),
+		Notes:      body.Notes,
+		State:      StateTodo,
+		CreatedAt:  time.Now(),
+	}
+	if body.Priority != nil {
+		task.Priority = *body.Priority
+	}
+	if task.CategoryID == "" && len(cfg.Categories) > 0 {
+		task.CategoryID = cfg.Categories[0].ID
+	}
+	if msg := checkTask(cfg, task); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	task.logEvent("created", "", task.CreatedAt)
+	cfg.Tasks = append(cfg.Tasks, task)
+	if err := saveConfig(cfg); err != nil {
+		writeError(w, http.StatusInternalServerError, "error saving config: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, task)
+}
+
+func (s *apiServer) updateTask(w http.ResponseWriter, r *http.Request) {
+	if !s.writable(w) {
+		return
+	}
+	var patch taskPatch
+	if !readBody(w, r, &patch) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.lock(w)
+	if !ok {
+		return
+	}
+	defer lock.Close()
+	cfg, ok := s.load(w)
+	if !ok {
+		return
+	}
+
+	var task *Task
+	for i := range cfg.Tasks {
+		if cfg.Tasks[i].ID == r.PathValue("id") {
+			task = &cfg.Tasks[i]
+			break
+		}
+	}
+	if task == nil {
+		writeError(w, http.StatusNotFound, "no task "+r.PathValue("id"))
+		return
+	}
+
+	// Check the result before touching the task, then apply the same
+	// history events the edit form logs
+	next := *task
+	if patch.Content != nil {
+		next.Content = strings.TrimSpace(*patch.Content)
+	}
+	if patch.Priority != nil {
+		next.Priority = *patch.Priority
+	}
+	if patch.CategoryID != nil {
+		next.CategoryID = *patch.CategoryID
+	}
+	if msg := checkTask(cfg, next); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	if patch.State != nil {
+		switch *patch.State {
+		case StateTodo, StateDoing, StateWaiting, StateDone:
+		default:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown state %q (want todo, doing, waiting, or done)", *patch.State))
+			return
+		}
+	}
+
+	now := time.Now()
+	if next.Content != task.Content {
+		task.logEvent("edited", next.Content, now)
+		task.Content = next.Content
+	}
+	if next.Priority != task.Priority {
+		task.logEvent("priority", task.Priority.String()+" → "+next.Priority.String(), now)
+		task.Priority = next.Priority
+	}
+	if next.CategoryID != task.CategoryID {
+		task.logEvent("recategorized", cfg.categoryName(task.CategoryID)+" → "+cfg.categoryName(next.CategoryID), now)
+		task.CategoryID = next.CategoryID
+	}
+	if patch.URLs != nil {
+		task.setURLs(cleanURLs(*patch.URLs), now)
+	}
+	if patch.Notes != nil {
+		task.setNotes(*patch.Notes, now)
+	}
+	if patch.State != nil {
+		task.setState(*patch.State, now)
+	}
+
+	if err := saveConfig(cfg); err != nil {
+		writeError(w, http.StatusInternalServerError, "error saving config: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+// checkTask returns why a task from the API can't be saved, or ""
+func checkTask(cfg *Config, task Task) string {
+	switch {
+	case task.Content == "":
+		return "content is required"
+	case task.Priority < P0Critical || task.Priority > P3Low:
+		return fmt.Sprintf("invalid priority %d (want 0-3)", task.Priority)
+	}
+	for _, cat := range cfg.Categories {
+		if cat.ID == task.CategoryID {
+			return ""
+		}
+	}
+	return fmt.Sprintf("unknown category %q", task.CategoryID)
+}
+
+// runDedupe implements `todobi dedupe [--yes]`
+func runDedupe(args []string) error {
+	cfg, err := loadCLIConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	out := infoOut()
+	duplicates := findDuplicates(cfg)
+	if len(duplicates) == 0 {
+		fmt.Fprintln(out, "No duplicate tasks found.")
+		return echoConfig(cfg)
+	}
+
+	count := 0
+	for _, group := range duplicates {
+		fmt.Fprintf(out, "Keep:   %s (%s)\n", group[0].Content, group[0].ID)
+		for _, task := range group[1:] {
+			fmt.Fprintf(out, "Remove: %s (%s)\n", task.Content, task.ID)
+			count++
+		}
+		fmt.Fprintln(out)
+	}
+
+	confirmed := false
+	for _, arg := range args {
+		if arg == "--yes" || arg == "-y" {
+			confirmed = true
+		}
+	}
+	if !confirmed && stdioConfig() {
+		return fmt.Errorf("stdin holds the config, so dedupe can't ask; pass --yes")
+	}
+	if !confirmed {
+		fmt.Fprintf(out, "Remove %d duplicate tasks? [y/N] ", count)
+		var answer string
+		fmt.Scanln(&answer)
+		confirmed = strings.EqualFold(strings.TrimSpace(answer), "y")
+	}
+	if !confirmed {
+		fmt.Fprintln(out, "Nothing removed.")
+		return nil
+	}
+
+	removed := removeDuplicates(cfg, duplicates)
+	if err := saveConfig(cfg); err != nil {
+		return fmt.Errorf("error saving config: %w", err)
+	}
+	fmt.Fprintf(out, "Removed %d duplicate tasks.\n", removed)
+	return nil
+}
+
+// Bubble Tea interface
+func (m model) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, tickCmd())
+}
+
+// Update handles msg, then retitles the terminal if updateLists changed
+// the pending counts
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(model); ok && nm.windowTitle != m.windowTitle {
+		return nm, tea.Batch(cmd, tea.SetWindowTitle(nm.windowTitle))
+	}
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = max(msg.Width, minWidth)
+		m.height = max(msg.Height, minHeight)
+
+		// Adjust list height to account for header (3 ASCII + 1 gray + 4 tabs + footer)
+		listHeight := m.height - 12
+		m.list.SetSize(m.width, listHeight)
+		m.completedList.SetSize(m.width, listHeight)
+		m.snoozedList.SetSize(m.width, listHeight)
+		m.backlogList.SetSize(m.width, listHeight)
+		m.categoryList.SetSize(m.width, listHeight)
+
+		if !m.ready {
+			m.ready = true
+			m.updateLists()
+		}
+		return m, nil
+
+	case tickMsg:
+		if d := m.config.idleLock(); d > 0 && time.Time(msg).Sub(m.lastInput) >= d {
+			m.locked = true
+		}
+		switch {
+		case m.visual:
+			// Leave the list alone under an open V selection; a later
+			// tick catches up
+		case !m.nextSnoozeWake.IsZero() && !time.Time(msg).Before(m.nextSnoozeWake):
+			// Bring snoozed tasks back once their snooze expires
+			m.updateLists()
+			m.setStatus("Snoozed task is back")
+		default:
+			// Keep time-based ordering current during long sessions
+			m.resortLists()
+		}
+		return m, tickCmd()
+
+	case signalMsg:
+		// Keep in-progress notes, then save and exit like q
+		m.flushNotes()
+		return m.quit()
+
+	case notesAutosaveMsg:
+		if msg.seq != m.notesEditSeq || m.mode != taskDetailView || m.showingSaveConfirm {
+			return m, nil
+		}
+		m.flushNotes()
+		m.originalNotes = strings.TrimSpace(m.notesTextarea.Value())
+		m.notesAutosaved = true
+		return m, nil
+
+	case focusTickMsg:
+		now := time.Time(msg)
+		switch {
+		case m.focusTaskID != "" && !now.Before(m.focusUntil):
+			m.logFocus(m.config.focusDuration())
+			m.breakUntil = now.Add(m.config.breakDuration())
+			m.setStatus(fmt.Sprintf("Focus session done! Take a %d-minute break (f skips)", int(m.config.breakDuration().Minutes())))
+			return m, focusTickCmd()
+		case m.focusTaskID != "":
+			return m, focusTickCmd()
+		case !m.breakUntil.IsZero() && !now.Before(m.breakUntil):
+			m.breakUntil = time.Time{}
+			m.setStatus("Break's over - press f to focus again")
+			return m, nil
+		case !m.breakUntil.IsZero():
+			return m, focusTickCmd()
+		}
+		return m, nil
+
+	case dedupeScanMsg:
+		m.busy = ""
+		if len(msg.duplicates) == 0 {
+			m.setStatus("No duplicate tasks found")
+			return m, nil
+		}
+		if m.mode != listView && m.mode != completedView && m.mode != snoozedView && m.mode != backlogView {
+			// The user moved on to a form; don't yank them into the dialog
+			m.setStatus("Duplicates found - press D again to review")
+			return m, nil
+		}
+		m.endVisual()
+		m.duplicates = msg.duplicates
+		m.prevMode = m.mode
+		m.mode = dedupeConfirmView
+		return m, nil
+
+	case syncResultMsg:
+		if !m.syncInProgress {
+			// Sync was cancelled; drop the late result
+			return m, nil
+		}
+		m.cancelSync()
+		m.syncInProgress = false
+		if m.mode == firstRunView {
+			// Handle first-run sync completion
+			if msg.success {
+				m.firstRunStep = completeStep
+				m.firstRunError = ""
+			} else {
+				m.firstRunError = msg.error
+				m.recordSyncError(msg.error, msg.details)
+				// Allow user to continue despite error
+			}
+			return m, nil
+		}
+		if msg.success {
+			m.setStatus("Synced to GitHub successfully!")
+			m.configChanged = false
+		} else {
+			m.setStatus("Sync failed: " + msg.error + m.recordSyncError(msg.error, msg.details))
+		}
+		m.mode = m.prevMode
+		return m, nil
+
+	case pullResultMsg:
+		if !m.pullInProgress {
+			// Pull was cancelled; drop the late result
+			return m, nil
+		}
+		m.cancelSync()
+		m.pullInProgress = false
+		if m.mode == firstRunView && m.firstRunStep == pushingStep {
+			// Creating the repo checks for an existing one first, so a
+			// new machine can't clobber the real remote
+			if msg.noRemote {
+				m.syncInProgress = true
+				return m, m.startSync()
+			}
+			if !msg.success {
+				m.firstRunError = msg.error
+				m.recordSyncError(msg.error, msg.details)
+				return m, nil
+			}
+			m.config.GitHubSetupComplete = true
+			m.saveConfigAndMarkChanged()
+			m.updateLists()
+			m.remoteConfig = msg.remoteConfig
+			m.prevMode = m.postSetupView()
+			m.mode = pullConfirmView
+			m.setStatus(m.config.syncRepoPath() + " already has tasks - choose how to combine them")
+			return m, nil
+		}
+		if m.mode == firstRunView {
+			// Handle first-run pull completion
+			if msg.success {
+				// Apply remote config without conflict checking on first run
+				m.config = msg.remoteConfig
+				m.updateLists()
+				m.firstRunStep = completeStep
+				m.firstRunError = ""
+			} else {
+				m.firstRunError = msg.error
+				m.recordSyncError(msg.error, msg.details)
+				// Allow user to continue despite error
+			}
+			return m, nil
+		}
+		if msg.success {
+			if msg.hasConflict {
+				// Store remote config for conflict resolution
+				m.remoteConfig = msg.remoteConfig
+				m.setStatus("Conflict detected - choose merge strategy")
+				m.endVisual()
+				m.mode = pullConfirmView
+			} else {
+				// No conflict, just apply the remote config
+				m.config = msg.remoteConfig
+				m.updateLists()
+				m.configChanged = false
+				m.setStatus("Pulled from GitHub successfully!")
+				m.mode = m.prevMode
+			}
+		} else {
+			m.setStatus("Pull failed: " + msg.error + m.recordSyncError(msg.error, msg.details))
+			m.mode = m.prevMode
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		m.lastInput = time.Now()
+		if m.locked {
+			// The key that unlocks does nothing else
+			m.locked = false
+			return m, nil
+		}
+		if m.readonly && !m.searching && !m.readonlyAllows(msg.String()) {
+			m.setStatus("Read-only mode")
+			return m, nil
+		}
+
+		if m.searching {
+			return m.handleSearch(msg)
+		}
+		if m.visual {
+			return m.handleVisual(msg)
+		}
+		if m.linkChoices != nil {
+			return m.handleLinkChoice(msg)
+		}
+
+		// Form handling
+		if m.mode == firstRunView {
+			return m.handleFirstRun(msg)
+		}
+		if m.mode == categoryFormView {
+			return m.handleCategoryForm(msg)
+		}
+		if m.mode == taskFormView {
+			return m.handleTaskForm(msg)
+		}
+		if m.mode == editTaskView {
+			return m.handleTaskEdit(msg)
+		}
+		if m.mode == taskDetailView {
+			return m.handleTaskDetail(msg)
+		}
+		if m.mode == deleteConfirmView {
+			return m.handleDeleteConfirm(msg)
+		}
+		if m.mode == categoryListView {
+			return m.handleCategoryList(msg)
+		}
+		if m.mode == syncConfirmView {
+			return m.handleSyncConfirm(msg)
+		}
+		if m.mode == pullConfirmView {
+			return m.handlePullConfirm(msg)
+		}
+		if m.mode == reloadConfirmView {
+			return m.handleReloadConfirm(msg)
+		}
+		if m.mode == dedupeConfirmView {
+			return m.handleDedupeConfirm(msg)
+		}
+		if m.mode == createdDateView {
+			return m.handleCreatedDate(msg)
+		}
+		if m.mode == categoryNotesView {
+			return m.handleCategoryNotes(msg)
+		}
+		if m.mode == tourView {
+			return m.handleTour(msg)
+		}
+		if m.mode == snoozeDateView {
+			return m.handleSnoozeDate(msg)
+		}
+		if m.mode == dashboardView {
+			return m.handleDashboard(msg)
+		}
+		if m.mode == categoryMergeView {
+			return m.handleCategoryMerge(msg)
+		}
+		if m.mode == completeCategoryView {
+			return m.handleCompleteCategory(msg)
+		}
+		if m.mode == syncErrorView {
+			return m.handleSyncError(msg)
+		}
+		if m.mode == resolveConflictsView {
+			return m.handleResolveConflicts(msg)
+		}
+
+		// Handle tab navigation in list view
+		if m.mode == listView || m.mode == completedView || m.mode == backlogView {
+			switch msg.String() {
+			case "tab":
+				return m.nextCategory()
+			case "shift+tab":
+				return m.prevCategory()
+			}
+		}
+
+		// Main view handling
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m.quit()
+
+		case "r":
+			return m.reloadConfig()
+
+		case "v":
+			if m.mode == completedView {
+				m.mode = listView
+			} else {
+				m.prevMode = m.mode
+				m.mode = completedView
+			}
+			return m, nil
+
+		case "c":
+			m.prevMode = m.mode
+			m.mode = categoryListView
+			m.updateCategoryList()
+			return m, nil
+
+		case "C":
+			m.prevMode = m.mode
+			m.mode = categoryFormView
+			m.editingCategory = nil
+			m.categoryColorIndex = 0
+			m.categoryInput.Focus()
+			m.categoryInput.SetValue("")
+			return m, textinput.Blink
+
+		case "T":
+			m.prevMode = m.mode
+			m.mode = taskFormView
+			m.formFocus = 0
+			m.taskInputs[0].Focus()
+			m.taskInputs[1].Blur()
+			m.taskInputs[2].Blur()
+			m.taskInputs[0].SetValue("")
+			m.taskInputs[1].SetValue("1")
+			m.taskInputs[2].SetValue("")
+			return m, textinput.Blink
+
+		case "S":
+			if m.mode == snoozedView {
+				m.mode = listView
+			} else {
+				m.prevMode = m.mode
+				m.mode = snoozedView
+			}
+			return m, nil
+
+		case "B":
+			if m.mode == backlogView {
+				m.mode = listView
+			} else {
+				m.prevMode = m.mode
+				m.mode = backlogView
+			}
+			return m, nil
+
+		case "M":
+			return m.toggleBacklog()
+
+		case "z":
+			if m.mode == snoozedView {
+				return m.snoozeTask(time.Time{})
+			}
+			return m.snoozeTask(startOfDay(time.Now()).AddDate(0, 0, 1))
+
+		case "Z":
+			return m.snoozeTask(startOfDay(time.Now()).AddDate(0, 0, 7))
+
+		case "u":
+			if task, ok := m.selectedTask(); ok && !task.Done {
+				m.prevMode = m.mode
+				m.mode = snoozeDateView
+				m.dateErr = ""
+				m.dateInput.SetValue("")
+				m.dateInput.Focus()
+				return m, textinput.Blink
+			}
+			return m, nil
+
+		case "F":
+			return m.cycleFilter()
+
+		case "s":
+			return m.cycleState()
+
+		case "P":
+			if readonlyFlag {
+				return m, nil
+			}
+			m.readonly = !m.readonly
+			if m.readonly {
+				m.setStatus("Read-only mode on - P to leave")
+			} else {
+				m.setStatus("Read-only mode off")
+			}
+			return m, nil
+
+		case "E":
+			if m.syncError == "" {
+				m.setStatus("No sync errors this session")
+				return m, nil
+			}
+			m.prevMode = m.mode
+			m.mode = syncErrorView
+			return m, nil
+
+		case "shift+right":
+			return m.cycleCategory(1)
+
+		case "shift+left":
+			return m.cycleCategory(-1)
+
+		case "f":
+			return m.toggleFocus()
+
+		case "b":
+			if m.mode == listView {
+				m.mode = dashboardView
+				m.dashCursor = 0
+			}
+			return m, nil
+
+		case "R":
+			m.resortLists()
+			m.setStatus("Resorted")
+			return m, nil
+
+		case "O", "~":
+			if msg.String() == "O" {
+				next := (slices.Index(sortModes, m.config.sortMode()) + 1) % len(sortModes)
+				m.config.SortMode = sortModes[next]
+			} else {
+				m.config.SortDesc = !m.config.SortDesc
+			}
+			m.saveConfigAndMarkChanged()
+			m.resortLists()
+			m.setStatus("Sort: " + m.config.sortLabel())
+			return m, nil
+
+		case "!":
+			if m.mode == listView || m.mode == dashboardView {
+				m.fireDrill = !m.fireDrill
+				m.dashCursor = 0
+				m.updateLists()
+			}
+			return m, nil
+
+		case "V":
+			if m.mode == listView && len(m.list.Items()) > 0 {
+				m.visual = true
+				m.visualOp = ""
+				m.visualAnchor = m.list.SelectedItem().(TaskItem).ID
+				m.list.SetDelegate(visualDelegate{ItemDelegate: m.listDelegate(), anchor: m.list.Index()})
+			}
+			return m, nil
+
+		case "/":
+			if m.mode == listView {
+				m.searching = true
+				m.searchRecall = len(m.searchHistory)
+				m.searchInput.SetValue(m.searchQuery)
+				m.searchInput.CursorEnd()
+				return m, m.searchInput.Focus()
+			}
+			return m, nil
+
+		case "esc":
+			if m.mode == listView && m.searchQuery != "" {
+				m.searchQuery = ""
+				m.updateLists()
+				m.setStatus("Search cleared")
+			}
+			return m, nil
+
+		case "L":
+			m.hideLowPriority = !m.hideLowPriority
+			m.updateLists()
+			if m.hideLowPriority {
+				m.setStatus("Hiding low priority tasks")
+			} else {
+				m.setStatus("Showing all priorities")
+			}
+			return m, nil
+
+		case "W":
+			return m.nextWorkspace()
+
+		case "D":
+			if m.busy != "" {
+				return m, nil
+			}
+			m.busy = "Scanning for duplicates"
+			tasks := append([]Task(nil), m.config.Tasks...)
+			return m, tea.Batch(m.spinner.Tick, findDuplicatesCmd(tasks))
+
+		case "t":
+			m.config.ColumnView = !m.config.ColumnView
+			m.applyListDelegates()
+			m.saveConfigAndMarkChanged()
+			return m, nil
+
+		case "x", " ":
+			return m.toggleTask()
+
+		case "X":
+			if m.mode != listView {
+				return m, nil
+			}
+			if m.selectedCategoryID == "" {
+				m.setStatus("Pick a category tab to complete it")
+				return m, nil
+			}
+			if m.config.pendingIn(m.selectedCategoryID) == 0 {
+				m.setStatus("Nothing left to complete in " + m.config.categoryName(m.selectedCategoryID))
+				return m, nil
+			}
+			m.prevMode = m.mode
+			m.mode = completeCategoryView
+			return m, nil
+
+		case "d":
+			return m.confirmDelete()
+
+		case "enter", "i":
+			return m.viewTaskDetail()
+
+		case "o":
+			if task, ok := m.selectedTask(); ok {
+				return m.openTaskLinks(task)
+			}
+			return m, nil
+
+		case "G":
+			m.prevMode = m.mode
+			if !m.config.confirmSync() {
+				m.syncInProgress = true
+				m.setStatus("Syncing to GitHub...")
+				return m, m.startSync()
+			}
+			m.mode = syncConfirmView
+			return m, nil
+
+		case "g":
+			m.prevMode = m.mode
+			m.pullInProgress = true
+			m.setStatus("Pulling from GitHub...")
+			return m, m.startPull()
+		}
+	}
+
+	// Handle spinner tick messages
+	if _, ok := msg.(spinner.TickMsg); ok && (m.syncInProgress || m.pullInProgress || m.busy != "" || m.mode == firstRunView) {
+		m.spinner, cmd = m.spinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	// Wrap from the last item to the first (and back) when enabled
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.config.WrapNavigation {
+		if l := m.activeList(); l != nil && wrapCursor(l, keyMsg.String()) {
+			return m, nil
+		}
+	}
+
+	// Update the active list
+	if m.mode == completedView {
+		m.completedList, cmd = m.completedList.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.mode == snoozedView {
+		m.snoozedList, cmd = m.snoozedList.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.mode == backlogView {
+		m.backlogList, cmd = m.backlogList.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.mode == listView {
+		m.list, cmd = m.list.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) nextCategory() (tea.Model, tea.Cmd) {
+	currentIndex := m.getCategoryIndex()
+	nextIndex := (currentIndex + 1) % (len(m.config.Categories) + 1)
+	return m.selectCategoryByIndex(nextIndex)
+}
+
+func (m model) prevCategory() (tea.Model, tea.Cmd) {
+	currentIndex := m.getCategoryIndex()
+	totalTabs := len(m.config.Categories) + 1
+	prevIndex := (currentIndex - 1 + totalTabs) % totalTabs
+	return m.selectCategoryByIndex(prevIndex)
+}
+
+func (m model) getCategoryIndex() int {
+	if m.selectedCategoryID == "" {
+		return 0 // "All" is index 0
+	}
+	for i, cat := range m.config.Categories {
+		if cat.ID == m.selectedCategoryID {
+			return i + 1 // +1 because "All" is at index 0
+		}
+	}
+	return 0
+}
+
+func (m model) selectCategoryByIndex(index int) (tea.Model, tea.Cmd) {
+	m.activeTabIndex = index
+	if index == 0 {
+		m.selectedCategoryID = "" // "All"
+	} else if index-1 < len(m.config.Categories) {
+		m.selectedCategoryID = m.config.Categories[index-1].ID
+	}
+	m.updateLists()
+	return m, nil
+}
+
+// listDelegate is the task row renderer for the configured layout
+func (m model) listDelegate() list.ItemDelegate {
+	if m.config.ColumnView {
+		return columnDelegate{}
+	}
+	return list.NewDefaultDelegate()
+}
+
+// applyListDelegates switches the task lists between the default two-line
+// layout and the aligned column layout
+func (m *model) applyListDelegates() {
+	delegate := m.listDelegate()
+	m.list.SetDelegate(delegate)
+	m.completedList.SetDelegate(delegate)
+	m.snoozedList.SetDelegate(delegate)
+	m.backlogList.SetDelegate(delegate)
+}
+
+// nextWorkspace saves the current workspace and switches to the next one
+func (m model) nextWorkspace() (tea.Model, tea.Cmd) {
+	names := listWorkspaces()
+	if len(names) < 2 {
+		m.setStatus("No other workspaces - start one with: todobi --workspace NAME")
+		return m, nil
+	}
+
+	// Switching drops the in-memory config, so stay put if it can't be saved
+	if err := saveConfig(m.config); err != nil {
+		m.setStatus("Error saving: " + err.Error() + " - staying on " + workspaceName())
+		return m, nil
+	}
+
+	next := names[0]
+	for i, name := range names {
+		if name == workspace {
+			next = names[(i+1)%len(names)]
+			break
+		}
+	}
+	// configPath follows the active workspace, so switch to lock and load
+	// it, and switch back if the new config can't be read
+	previous := workspace
+	setWorkspace(next)
+	var lock *os.File
+	var lockErr error
+	if !m.readonly {
+		lock, lockErr = sessionLock()
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		if lock != nil {
+			lock.Close()
+		}
+		setWorkspace(previous)
+		m.setStatus("Error loading workspace: " + err.Error())
+		return m, nil
+	}
+
+	// The old lock and any pull still running belong to the old workspace
+	if m.configLock != nil {
+		m.configLock.Close()
+	}
+	m.configLock = lock
+	m.cancelSync()
+	m.pullInProgress = false
+	m.syncInProgress = false
+
+	m.config = cfg
+	m.configChanged = false
+	m.activeTabIndex = 0
+	m.selectedCategoryID = ""
+	m.activeFilterIndex = 0
+	m.searchQuery = ""
+	m.applyListDelegates()
+	m.updateLists()
+	m.setStatus("Workspace: " + workspaceName())
+	if warning := newerConfigWarning(cfg); warning != "" {
+		m.setStatus("⚠ " + warning)
+	}
+	if lockErr != nil {
+		m.setStatus(lockStatus(lockErr))
+	}
+	return m, nil
+}
+
+// cycleFilter moves to the next saved smart list, wrapping back to no filter
+func (m model) cycleFilter() (tea.Model, tea.Cmd) {
+	if len(m.config.SavedFilters) == 0 {
+		m.setStatus("No saved filters - add saved_filters to the config")
+		return m, nil
+	}
+
+	m.activeFilterIndex = (m.activeFilterIndex + 1) % (len(m.config.SavedFilters) + 1)
+	if f, ok := m.activeFilter(); ok {
+		m.setStatus("Filter: " + f.Name)
+	} else {
+		m.setStatus("Filter cleared")
+	}
+	m.updateLists()
+	return m, nil
+}
+
+// activeLess orders pending tasks by category name, then by priority;
+// with stale_first, stale tasks lead their category
+func activeLess(a, b TaskItem) bool {
+	if a.CategoryName != b.CategoryName {
+		return a.CategoryName < b.CategoryName
+	}
+	if a.StaleFirst && a.Stale != b.Stale {
+		return a.Stale
+	}
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return tieLess(a, b)
+}
+
+// completedLess orders completed tasks by category, most recent first
+func completedLess(a, b TaskItem) bool {
+	if a.CategoryName != b.CategoryName {
+		return a.CategoryName < b.CategoryName
+	}
+	if !a.CompletedAt.Equal(b.CompletedAt) {
+		return a.CompletedAt.After(b.CompletedAt)
+	}
+	return tieLess(a, b)
+}
+
+// snoozedLess orders snoozed tasks so the soonest to wake comes first
+func snoozedLess(a, b TaskItem) bool {
+	if !a.SnoozedUntil.Equal(b.SnoozedUntil) {
+		return a.SnoozedUntil.Before(b.SnoozedUntil)
+	}
+	return tieLess(a, b)
+}
+
+// tieLess settles otherwise equal tasks oldest first, then by ID, so the
+// lists never shuffle between renders
+func tieLess(a, b TaskItem) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// resortLists reorders the items already in each list without re-reading
+// tasks from the config, keeping the cursor on the same task
+func (m *model) resortLists() {
+	resort := func(l *list.Model, less func(a, b TaskItem) bool) {
+		items := l.Items()
+		selected, _ := l.SelectedItem().(TaskItem)
+		sort.SliceStable(items, func(i, j int) bool {
+			return less(items[i].(TaskItem), items[j].(TaskItem))
+		})
+		l.SetItems(items)
+		for i, item := range items {
+			if item.(TaskItem).ID == selected.ID {
+				l.Select(i)
+				break
+			}
+		}
+	}
+	resort(&m.list, m.config.sorted(activeLess))
+	resort(&m.completedList, m.config.sorted(completedLess))
+	resort(&m.snoozedList, snoozedLess)
+	resort(&m.backlogList, m.config.sorted(activeLess))
+}
+
+func (m *model) updateLists() {
+	hyperlinks := m.config.hyperlinks()
+
+	now := time.Now()
+
+	// Helper to wrap a task with its category name and color
+	newItem := func(task Task) TaskItem {
+		item := TaskItem{
+			Task:         task,
+			CategoryName: "Unknown",
+			Times:        m.config.timeStyle(),
+			Hyperlinks:   hyperlinks,
+			Stale:        m.config.isStale(task, now),
+			StaleFirst:   m.config.StaleFirst,
+		}
+		for _, cat := range m.config.Categories {
+			if cat.ID == task.CategoryID {
+				item.CategoryName = cat.Name
+				item.CategoryColor = cat.Color
+				break
+			}
+		}
+		return item
+	}
+
+	m.nextSnoozeWake = time.Time{}
+	m.hiddenLowCount = 0
+
+	// Update active tasks list
+	var activeTasks []TaskItem
+	var snoozedTasks []TaskItem
+	var backlogTasks []TaskItem
+	for _, task := range m.config.Tasks {
+		if task.inBacklog() {
+			if m.selectedCategoryID == "" || task.CategoryID == m.selectedCategoryID {
+				backlogTasks = append(backlogTasks, newItem(task))
+			}
+			continue
+		}
+		if task.isSnoozed(now) {
+			snoozedTasks = append(snoozedTasks, newItem(task))
+			if m.nextSnoozeWake.IsZero() || task.SnoozedUntil.Before(m.nextSnoozeWake) {
+				m.nextSnoozeWake = task.SnoozedUntil
+			}
+			continue
+		}
+		if m.fireDrill {
+			// Everything on fire, regardless of tabs, filters, or search
+			if !task.Done && task.Priority == P0Critical {
+				activeTasks = append(activeTasks, newItem(task))
+			}
+			continue
+		}
+		if !task.Done {
+			// Filter by selected category if not "All"
+			if m.selectedCategoryID != "" && task.CategoryID != m.selectedCategoryID {
+				continue
+			}
+			if f, ok := m.activeFilter(); ok && !matchesFilter(task, f) {
+				continue
+			}
+			if m.searchQuery != "" && !matchesFilter(task, Filter{Contains: m.searchQuery}) {
+				continue
+			}
+			if m.hideLowPriority && task.Priority == P3Low {
+				m.hiddenLowCount++
+				continue
+			}
+			item := newItem(task)
+			item.Highlight = m.searchQuery
+			activeTasks = append(activeTasks, item)
+		}
+	}
+
+	activeOrder := m.config.sorted(activeLess)
+	sort.SliceStable(activeTasks, func(i, j int) bool {
+		return activeOrder(activeTasks[i], activeTasks[j])
+	})
+
+	var activeItems []list.Item
+	for _, task := range activeTasks {
+		activeItems = append(activeItems, task)
+	}
+	m.list.SetItems(activeItems)
+
+	// Update completed tasks list, narrowed by the same category tab
+	var completedTasks []TaskItem
+	for _, task := range m.config.Tasks {
+		if !task.Done {
+			continue
+		}
+		if m.selectedCategoryID != "" && task.CategoryID != m.selectedCategoryID {
+			continue
+		}
+		completedTasks = append(completedTasks, newItem(task))
+	}
+
+	completedOrder := m.config.sorted(completedLess)
+	sort.SliceStable(completedTasks, func(i, j int) bool {
+		return completedOrder(completedTasks[i], completedTasks[j])
+	})
+
+	var completedItems []list.Item
+	for _, task := range completedTasks {
+		completedItems = append(completedItems, task)
+	}
+	m.completedList.SetItems(completedItems)
+
+	sort.SliceStable(snoozedTasks, func(i, j int) bool {
+		return snoozedLess(snoozedTasks[i], snoozedTasks[j])
+	})
+
+	var snoozedItems []list.Item
+	for _, task := range snoozedTasks {
+		snoozedItems = append(snoozedItems, task)
+	}
+	m.snoozedList.SetItems(snoozedItems)
+
+	backlogOrder := m.config.sorted(activeLess)
+	sort.SliceStable(backlogTasks, func(i, j int) bool {
+		return backlogOrder(backlogTasks[i], backlogTasks[j])
+	})
+
+	var backlogItems []list.Item
+	for _, task := range backlogTasks {
+		backlogItems = append(backlogItems, task)
+	}
+	m.backlogList.SetItems(backlogItems)
+
+	// Keep a V selection on the tasks it started from
+	if m.visual {
+		if anchor := m.visualAnchorIndex(); anchor < 0 {
+			m.endVisual()
+		} else {
+			m.list.SetDelegate(visualDelegate{ItemDelegate: m.listDelegate(), anchor: anchor})
+		}
+	}
+
+	m.doneCount, m.totalCount = m.config.progress()
+
+	if m.config.showWindowTitle() {
+		m.windowTitle = windowTitleText(m.config, now)
+	}
+}
+
+// windowTitleText summarizes pending work for the terminal title,
+// e.g. "todobi — 3 P0, 12 total"
+func windowTitleText(cfg *Config, now time.Time) string {
+	total, critical := 0, 0
+	for _, task := range cfg.Tasks {
+		if task.Done || task.Backlog || task.isSnoozed(now) {
+			continue
+		}
+		total++
+		if task.Priority == P0Critical {
+			critical++
+		}
+	}
+
+	switch {
+	case total == 0:
+		return "todobi — all done"
+	case critical == 0:
+		return fmt.Sprintf("todobi — %d total", total)
+	default:
+		return fmt.Sprintf("todobi — %d P0, %d total", critical, total)
+	}
+}
+
+// activeList returns the task list shown in the current mode, if any
+func (m *model) activeList() *list.Model {
+	switch m.mode {
+	case completedView:
+		return &m.completedList
+	case snoozedView:
+		return &m.snoozedList
+	case backlogView:
+		return &m.backlogList
+	case listView:
+		return &m.list
+	}
+	return nil
+}
+
+// wrapCursor moves the cursor to the opposite end when the key would step
+// past the first or last item, and reports whether it did
+func wrapCursor(l *list.Model, k string) bool {
+	n := len(l.Items())
+	if n == 0 {
+		return false
+	}
+	switch k {
+	case "up", "k":
+		if l.Index() == 0 {
+			l.Select(n - 1)
+			return true
+		}
+	case "down", "j":
+		if l.Index() == n-1 {
+			l.Select(0)
+			return true
+		}
+	}
+	return false
+}
+
+// selectedTask returns the task under the cursor in the current task list
+func (m model) selectedTask() (Task, bool) {
+	var item list.Item
+	switch m.mode {
+	case completedView:
+		item = m.completedList.SelectedItem()
+	case snoozedView:
+		item = m.snoozedList.SelectedItem()
+	case backlogView:
+		item = m.backlogList.SelectedItem()
+	case listView:
+		item = m.list.SelectedItem()
+	case dashboardView:
+		tasks := m.dashboardTasks()
+		if len(tasks) == 0 {
+			return Task{}, false
+		}
+		return tasks[min(m.dashCursor, len(tasks)-1)].Task, true
+	}
+	if item == nil {
+		return Task{}, false
+	}
+	return item.(TaskItem).Task, true
+}
+
+// snoozeTask hides the selected task until the given time; a zero time wakes it
+func (m model) snoozeTask(until time.Time) (tea.Model, tea.Cmd) {
+	selectedTask, found := m.selectedTask()
+	if !found || selectedTask.Done {
+		return m, nil
+	}
+
+	for i := range m.config.Tasks {
+		if m.config.Tasks[i].ID == selectedTask.ID {
+			m.config.Tasks[i].SnoozedUntil = until
+			if until.IsZero() {
+				m.config.Tasks[i].logEvent("woken", "", time.Now())
+			} else {
+				m.config.Tasks[i].logEvent("snoozed", "until "+until.Format(m.config.dateFormat()), time.Now())
+			}
+			break
+		}
+	}
+
+	if until.IsZero() {
+		m.setStatus("Task woken up")
+	} else {
+		m.setStatus("Snoozed until " + until.Format("Mon Jan 2"))
+	}
+	m.saveConfigAndMarkChanged()
+	m.updateLists()
+	return m, nil
+}
+
+// toggleBacklog moves the selected pending task into the someday/maybe
+// backlog, or back onto the active list
+func (m model) toggleBacklog() (tea.Model, tea.Cmd) {
+	selectedTask, found := m.selectedTask()
+	if !found || selectedTask.Done {
+		return m, nil
+	}
+
+	for i := range m.config.Tasks {
+		if m.config.Tasks[i].ID == selectedTask.ID {
+			m.config.Tasks[i].Backlog = !selectedTask.Backlog
+			if selectedTask.Backlog {
+				m.config.Tasks[i].logEvent("activated", "from backlog", time.Now())
+			} else {
+				m.config.Tasks[i].logEvent("backlogged", "", time.Now())
+			}
+			break
+		}
+	}
+
+	if selectedTask.Backlog {
+		m.setStatus("Moved to the active list")
+	} else {
+		m.setStatus("Moved to the backlog - B to browse it")
+	}
+	m.saveConfigAndMarkChanged()
+	m.updateLists()
+	return m, nil
+}
+
+// openTaskLinks opens the task's link, or asks which one when it has
+// several
+func (m model) openTaskLinks(task Task) (tea.Model, tea.Cmd) {
+	switch len(task.URLs) {
+	case 0:
+		m.setStatus("No links on this task")
+	case 1:
+		m.openLink(task.URLs[0])
+	default:
+		m.linkChoices = task.URLs
+		m.setStatus(fmt.Sprintf("Open which link? 1-%d (any other key cancels)", min(len(task.URLs), 9)))
+	}
+	return m, nil
+}
+
+// handleLinkChoice opens the link whose number was pressed after o
+func (m model) handleLinkChoice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	links := m.linkChoices
+	m.linkChoices = nil
+	if n, err := strconv.Atoi(msg.String()); err == nil && n >= 1 && n <= len(links) {
+		m.openLink(links[n-1])
+	} else {
+		m.setStatus("Cancelled")
+	}
+	return m, nil
+}
+
+// openLink hands link to the desktop's default browser
+func (m *model) openLink(link string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	if err := cmd.Start(); err != nil {
+		m.setStatus("Could not open link: " + err.Error())
+		return
+	}
+	go cmd.Wait()
+	m.setStatus("Opened " + link)
+}
+
+// cycleState advances the selected task through todo, doing, waiting, done
+func (m model) cycleState() (tea.Model, tea.Cmd) {
+	selectedTask, found := m.selectedTask()
+	if !found {
+		return m, nil
+	}
+
+	next := selectedTask.state().next()
+	if selectedTask.Done {
+		// x already reopens to todo; s picks the work back up
+		next = StateDoing
+	}
+	for i := range m.config.Tasks {
+		if m.config.Tasks[i].ID == selectedTask.ID {
+			m.config.Tasks[i].setState(next, time.Now())
+			break
+		}
+	}
+
+	m.setStatus("Task marked " + string(next))
+	m.saveConfigAndMarkChanged()
+	m.updateLists()
+	return m, nil
+}
+
+// cycleCategory moves the selected task to the next (delta 1) or previous
+// (delta -1) category in config order, wrapping at either end. The cursor
+// follows the task unless a category tab now hides it.
+func (m model) cycleCategory(delta int) (tea.Model, tea.Cmd) {
+	selectedTask, found := m.selectedTask()
+	if !found || len(m.config.Categories) < 2 {
+		return m, nil
+	}
+
+	n := len(m.config.Categories)
+	current := 0
+	for i, cat := range m.config.Categories {
+		if cat.ID == selectedTask.CategoryID {
+			current = i
+			break
+		}
+	}
+	next := m.config.Categories[(current+delta+n)%n]
+
+	for i := range m.config.Tasks {
+		if m.config.Tasks[i].ID == selectedTask.ID {
+			m.config.Tasks[i].CategoryID = next.ID
+			m.config.Tasks[i].logEvent("recategorized", m.config.categoryName(selectedTask.CategoryID)+" → "+next.Name, time.Now())
+			break
+		}
+	}
+
+	m.setStatus("Moved to " + next.Name)
+	m.saveConfigAndMarkChanged()
+	m.updateLists()
+	if l := m.activeList(); l != nil {
+		for i, item := range l.Items() {
+			if item.(TaskItem).ID == selectedTask.ID {
+				l.Select(i)
+				break
+			}
+		}
+	}
+	return m, nil
+}
+
+// visualDelegate marks the rows between the anchor and the cursor with an
+// accent bar in place of their first column
+type visualDelegate struct {
+	list.ItemDelegate
+	anchor int
+}
+
+func (d visualDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if index < min(d.anchor, m.Index()) || index > max(d.anchor, m.Index()) {
+		d.ItemDelegate.Render(w, m, index, item)
+		return
+	}
+
+	var row strings.Builder
+	d.ItemDelegate.Render(&row, m, index, item)
+	bar := lipgloss.NewStyle().Foreground(colorAccent).Render("┃")
+	lines := strings.Split(row.String(), "\n")
+	for i, line := range lines {
+		lines[i] = bar + ansi.TruncateLeft(line, 1, "")
 	}
+	fmt.Fprint(w, strings.Join(lines, "\n"))
 }
 
-// Bubble Tea interface
-func (m model) Init() tea.Cmd {
-	return m.spinner.Tick
+// taskCount renders n as "1 task" or "N tasks"
+func taskCount(n int) string {
+	if n == 1 {
+		return "1 task"
+	}
+	return fmt.Sprintf("%d tasks", n)
 }
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	var cmds []tea.Cmd
+// visualAnchorIndex finds the task the V selection started on, which
+// moves if the list is rebuilt; -1 once it's gone
+func (m model) visualAnchorIndex() int {
+	for i, item := range m.list.Items() {
+		if item.(TaskItem).ID == m.visualAnchor {
+			return i
+		}
+	}
+	return -1
+}
 
-	switch msg := msg.(type) {
-	case tea.WindowSizeMsg:
-		m.width = max(msg.Width, minWidth)
-		m.height = max(msg.Height, minHeight)
+// visualTasks are the tasks in the V selection, top to bottom
+func (m model) visualTasks() []TaskItem {
+	items := m.list.Items()
+	anchor := m.visualAnchorIndex()
+	if anchor < 0 {
+		return nil
+	}
+	from := min(anchor, m.list.Index())
+	to := min(max(anchor, m.list.Index()), len(items)-1)
 
-		// Adjust list height to account for header (3 ASCII + 1 gray + 4 tabs + footer)
-		listHeight := m.height - 12
-		m.list.SetSize(m.width, listHeight)
-		m.completedList.SetSize(m.width, listHeight)
-		m.categoryList.SetSize(m.width, listHeight)
+	var tasks []TaskItem
+	for i := from; i <= to; i++ {
+		tasks = append(tasks, items[i].(TaskItem))
+	}
+	return tasks
+}
 
-		if !m.ready {
-			m.ready = true
-			m.updateLists()
-		}
-		return m, nil
+// endVisual leaves visual mode and restores the normal row renderer
+func (m *model) endVisual() {
+	m.visual = false
+	m.visualOp = ""
+	m.applyListDelegates()
+}
 
-	case syncResultMsg:
-		m.syncInProgress = false
-		if m.mode == firstRunView {
-			// Handle first-run sync completion
-			if msg.success {
-				m.firstRunStep = completeStep
-				m.firstRunError = ""
-			} else {
-				m.firstRunError = msg.error
-				// Allow user to continue despite error
-			}
-			return m, nil
-		}
-		if msg.success {
-			m.setStatus("Synced to GitHub successfully!")
-			m.configChanged = false
-		} else {
-			m.setStatus("Sync failed: " + msg.error)
+// handleVisual drives V range selection: j/k extend it, then an operator
+// acts on every selected task. d asks first (unless confirm_deletes is
+// off), m waits for a category number.
+func (m model) handleVisual(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if key == "ctrl+c" {
+		return m.quit()
+	}
+
+	switch m.visualOp {
+	case "d":
+		switch key {
+		case "y", "Y":
+			return m.applyVisual("d", "")
+		case "n", "N", "esc":
+			m.visualOp = ""
 		}
-		m.mode = m.prevMode
 		return m, nil
-
-	case pullResultMsg:
-		m.pullInProgress = false
-		if m.mode == firstRunView {
-			// Handle first-run pull completion
-			if msg.success {
-				// Apply remote config without conflict checking on first run
-				m.config = msg.remoteConfig
-				m.updateLists()
-				m.firstRunStep = completeStep
-				m.firstRunError = ""
-			} else {
-				m.firstRunError = msg.error
-				// Allow user to continue despite error
-			}
+	case "m":
+		if key == "esc" {
+			m.visualOp = ""
 			return m, nil
 		}
-		if msg.success {
-			if msg.hasConflict {
-				// Store remote config for conflict resolution
-				m.remoteConfig = msg.remoteConfig
-				m.setStatus("Conflict detected - choose merge strategy")
-				m.mode = pullConfirmView
-			} else {
-				// No conflict, just apply the remote config
-				m.config = msg.remoteConfig
-				m.updateLists()
-				m.configChanged = false
-				m.setStatus("Pulled from GitHub successfully!")
-				m.mode = m.prevMode
-			}
-		} else {
-			m.setStatus("Pull failed: " + msg.error)
-			m.mode = m.prevMode
+		if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= min(len(m.config.Categories), 9) {
+			return m.applyVisual("m", m.config.Categories[n-1].ID)
 		}
 		return m, nil
+	}
 
-	case tea.KeyMsg:
-		// Form handling
-		if m.mode == firstRunView {
-			return m.handleFirstRun(msg)
-		}
-		if m.mode == categoryFormView {
-			return m.handleCategoryForm(msg)
-		}
-		if m.mode == taskFormView {
-			return m.handleTaskForm(msg)
-		}
-		if m.mode == editTaskView {
-			return m.handleTaskEdit(msg)
-		}
-		if m.mode == taskDetailView {
-			return m.handleTaskDetail(msg)
+	var cmd tea.Cmd
+	switch key {
+	case "esc", "V":
+		m.endVisual()
+	case "j", "k", "up", "down", "pgup", "pgdown":
+		m.list, cmd = m.list.Update(msg)
+	case "d":
+		if !m.config.confirmDeletes() {
+			return m.applyVisual("d", "")
 		}
-		if m.mode == deleteConfirmView {
-			return m.handleDeleteConfirm(msg)
+		m.visualOp = "d"
+	case "x", " ":
+		return m.applyVisual("x", "")
+	case "m":
+		if len(m.config.Categories) > 0 {
+			m.visualOp = "m"
 		}
-		if m.mode == categoryListView {
-			return m.handleCategoryList(msg)
+	}
+	return m, cmd
+}
+
+// applyVisual runs an operator over the selected tasks and leaves visual
+// mode: d deletes, x completes, m moves them to categoryID
+func (m model) applyVisual(op, categoryID string) (tea.Model, tea.Cmd) {
+	selected := make(map[string]bool)
+	for _, task := range m.visualTasks() {
+		selected[task.ID] = true
+	}
+	now := time.Now()
+
+	switch op {
+	case "d":
+		kept := m.config.Tasks[:0]
+		for _, task := range m.config.Tasks {
+			if !selected[task.ID] {
+				kept = append(kept, task)
+			}
 		}
-		if m.mode == syncConfirmView {
-			return m.handleSyncConfirm(msg)
+		m.config.Tasks = kept
+		m.setStatus("Deleted " + taskCount(len(selected)))
+	case "x":
+		for i := range m.config.Tasks {
+			if selected[m.config.Tasks[i].ID] && !m.config.Tasks[i].Done {
+				m.config.Tasks[i].setState(StateDone, now)
+			}
 		}
-		if m.mode == pullConfirmView {
-			return m.handlePullConfirm(msg)
+		m.setStatus("Completed " + taskCount(len(selected)))
+	case "m":
+		name := m.config.categoryName(categoryID)
+		for i := range m.config.Tasks {
+			task := &m.config.Tasks[i]
+			if selected[task.ID] && task.CategoryID != categoryID {
+				task.logEvent("recategorized", m.config.categoryName(task.CategoryID)+" → "+name, now)
+				task.CategoryID = categoryID
+			}
 		}
+		m.setStatus(fmt.Sprintf("Moved %s to %s", taskCount(len(selected)), name))
+	}
 
-		// Handle tab navigation in list view
-		if m.mode == listView || m.mode == completedView {
-			switch msg.String() {
-			case "tab":
-				return m.nextCategory()
-			case "shift+tab":
-				return m.prevCategory()
+	m.endVisual()
+	m.saveConfigAndMarkChanged()
+	m.updateLists()
+	return m, nil
+}
+
+// browseKeys move around and switch views without changing anything; they
+// are all that works in read-only mode
+var browseKeys = map[string]bool{
+	"up": true, "down": true, "k": true, "j": true, "left": true, "right": true,
+	"pgup": true, "pgdown": true, "home": true, "end": true,
+	"tab": true, "shift+tab": true, "enter": true, "i": true, "esc": true,
+	"v": true, "S": true, "B": true, "b": true, "c": true, "F": true, "L": true, "R": true,
+	"E": true, "P": true, "o": true, "?": true, "/": true, "!": true, "q": true, "ctrl+c": true,
+}
+
+// readonlyAllows reports whether key may run in read-only mode. Views that
+// edit on ordinary keys (notes, category rename) only let you leave.
+func (m model) readonlyAllows(key string) bool {
+	if m.linkChoices != nil {
+		return true
+	}
+	switch m.mode {
+	case listView, completedView, snoozedView, backlogView, dashboardView:
+		return browseKeys[key]
+	case categoryListView:
+		return browseKeys[key] && key != "c"
+	case syncErrorView:
+		return true
+	case taskDetailView:
+		return key == "esc" || key == "ctrl+y" || key == "ctrl+o" || key == "ctrl+c"
+	default:
+		return key == "esc" || key == "ctrl+c"
+	}
+}
+
+// quit saves and exits, remembering today's completions for the summary
+// printed after the TUI closes
+func (m model) quit() (tea.Model, tea.Cmd) {
+	if m.readonly {
+		return m, tea.Quit
+	}
+
+	// From a form or detail view, remember the view underneath it
+	mode := m.mode
+	if _, ok := lastViewNames[mode]; !ok {
+		mode = m.prevMode
+	}
+	if name, ok := lastViewNames[mode]; ok {
+		m.config.LastView = name
+	}
+	saveConfig(m.config)
+	if m.config.quitSummary() {
+		m.completedToday = m.config.completedOn(time.Now())
+	}
+	return m, tea.Quit
+}
+
+// toggleFocus starts a focus session on the selected task, or stops the
+// running session (crediting the time spent so far) or skips a break
+func (m model) toggleFocus() (tea.Model, tea.Cmd) {
+	if m.focusTaskID != "" {
+		elapsed := m.config.focusDuration() - time.Until(m.focusUntil)
+		m.logFocus(elapsed)
+		m.setStatus("Focus session stopped")
+		return m, nil
+	}
+
+	if !m.breakUntil.IsZero() {
+		m.breakUntil = time.Time{}
+		m.setStatus("Break skipped")
+		return m, nil
+	}
+
+	task, ok := m.selectedTask()
+	if !ok || task.Done {
+		return m, nil
+	}
+	m.focusTaskID = task.ID
+	m.focusContent = task.Content
+	m.focusUntil = time.Now().Add(m.config.focusDuration())
+	m.setStatus("Focus started")
+	return m, focusTickCmd()
+}
+
+// logFocus credits whole minutes of focus to the task and ends the session
+func (m *model) logFocus(spent time.Duration) {
+	minutes := int(spent.Minutes())
+	if minutes > 0 {
+		for i := range m.config.Tasks {
+			if m.config.Tasks[i].ID == m.focusTaskID {
+				m.config.Tasks[i].SpentMinutes += minutes
+				m.config.Tasks[i].logEvent("focus", fmt.Sprintf("%d min", minutes), time.Now())
+				break
 			}
 		}
+		m.saveConfigAndMarkChanged()
+		m.updateLists()
+	}
+	m.focusTaskID = ""
+	m.focusContent = ""
+	m.focusUntil = time.Time{}
+}
 
-		// Main view handling
-		switch msg.String() {
-		case "q", "ctrl+c":
-			saveConfig(m.config)
-			return m, tea.Quit
+// renderFocusTimer shows the running focus or break countdown, if any
+func (m model) renderFocusTimer() string {
+	countdown := func(until time.Time) string {
+		left := time.Until(until).Round(time.Second)
+		if left < 0 {
+			left = 0
+		}
+		return fmt.Sprintf("%02d:%02d", int(left.Minutes()), int(left.Seconds())%60)
+	}
 
-		case "r":
-			cfg, err := loadConfig()
-			if err != nil {
-				m.setStatus("Error reloading config")
-			} else {
-				m.config = cfg
-				m.updateLists()
-				m.setStatus("Config reloaded")
-			}
-			return m, nil
+	switch {
+	case m.focusTaskID != "":
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("#d73a4a")).Bold(true)
+		return style.Render("🍅 "+countdown(m.focusUntil)) + " " + ansi.Truncate(m.focusContent, 20, "…") + " "
+	case !m.breakUntil.IsZero():
+		return lipgloss.NewStyle().Foreground(colorAccent).Render("☕ "+countdown(m.breakUntil)) + " "
+	}
+	return ""
+}
 
-		case "v":
-			if m.mode == completedView {
-				m.mode = listView
-			} else {
-				m.prevMode = m.mode
-				m.mode = completedView
-			}
-			return m, nil
+// startOfDay truncates t to local midnight
+func startOfDay(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
 
-		case "c":
-			m.prevMode = m.mode
-			m.mode = categoryListView
-			m.updateCategoryList()
-			return m, nil
+func (m *model) updateCategoryList() {
+	var items []list.Item
+	for _, cat := range m.config.Categories {
+		items = append(items, cat)
+	}
+	m.categoryList.SetItems(items)
+}
 
-		case "C":
-			m.prevMode = m.mode
-			m.mode = categoryFormView
-			m.editingCategory = nil
-			m.categoryInput.Focus()
-			m.categoryInput.SetValue("")
-			return m, textinput.Blink
+func (m model) toggleTask() (tea.Model, tea.Cmd) {
+	selectedTask, found := m.selectedTask()
 
-		case "T":
-			m.prevMode = m.mode
-			m.mode = taskFormView
-			m.formFocus = 0
-			m.taskInputs[0].Focus()
-			m.taskInputs[1].Blur()
-			m.taskInputs[0].SetValue("")
-			m.taskInputs[1].SetValue("1")
-			return m, textinput.Blink
+	if !found {
+		return m, nil
+	}
 
-		case "x", " ":
-			return m.toggleTask()
+	// Find and toggle the task in config
+	for i := range m.config.Tasks {
+		if m.config.Tasks[i].ID == selectedTask.ID {
+			if m.config.Tasks[i].Done {
+				m.config.Tasks[i].setState(StateTodo, time.Now())
+				m.setStatus("Task reopened")
+			} else {
+				m.config.Tasks[i].setState(StateDone, time.Now())
+				m.setStatus("Task completed")
+			}
+			break
+		}
+	}
 
-		case "d":
-			return m.confirmDelete()
+	m.saveConfigAndMarkChanged()
+	m.updateLists()
+	return m, nil
+}
 
-		case "enter", "i":
-			return m.viewTaskDetail()
+func (m model) confirmDelete() (tea.Model, tea.Cmd) {
+	selectedTask, found := m.selectedTask()
 
-		case "G":
-			m.prevMode = m.mode
-			m.mode = syncConfirmView
-			return m, nil
+	if !found {
+		return m, nil
+	}
 
-		case "g":
-			m.prevMode = m.mode
-			m.pullInProgress = true
-			m.setStatus("Pulling from GitHub...")
-			return m, tea.Batch(pullFromGitHubCmd(m.config), m.spinner.Tick)
-		}
+	m.taskToDelete = &selectedTask
+	m.prevMode = m.mode
+	if !m.config.confirmDeletes() {
+		return m.deleteTask()
 	}
+	m.mode = deleteConfirmView
+	return m, nil
+}
 
-	// Handle spinner tick messages
-	if _, ok := msg.(spinner.TickMsg); ok && (m.syncInProgress || m.pullInProgress || m.mode == firstRunView) {
-		m.spinner, cmd = m.spinner.Update(msg)
-		cmds = append(cmds, cmd)
+func (m model) deleteTask() (tea.Model, tea.Cmd) {
+	if m.taskToDelete == nil {
+		return m, nil
 	}
 
-	// Update the active list
-	if m.mode == completedView {
-		m.completedList, cmd = m.completedList.Update(msg)
-		cmds = append(cmds, cmd)
-	} else if m.mode == listView {
-		m.list, cmd = m.list.Update(msg)
-		cmds = append(cmds, cmd)
+	// Find and delete the task
+	for i := range m.config.Tasks {
+		if m.config.Tasks[i].ID == m.taskToDelete.ID {
+			m.config.Tasks = append(m.config.Tasks[:i], m.config.Tasks[i+1:]...)
+			break
+		}
 	}
 
-	return m, tea.Batch(cmds...)
+	m.saveConfigAndMarkChanged()
+	m.updateLists()
+	m.setStatus("Task deleted")
+	m.taskToDelete = nil
+	m.mode = m.prevMode
+	return m, nil
 }
 
-func (m model) nextCategory() (tea.Model, tea.Cmd) {
-	currentIndex := m.getCategoryIndex()
-	nextIndex := (currentIndex + 1) % (len(m.config.Categories) + 1)
-	return m.selectCategoryByIndex(nextIndex)
+func (m model) handleDeleteConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		if m.taskToDelete != nil {
+			return m.deleteTask()
+		} else if m.categoryToDelete != nil {
+			return m.deleteCategory(m.config.categoryDelete())
+		}
+	case "r", "R", "D":
+		// A category with tasks can pick what happens to them; deleting
+		// them takes a capital D so a double-tapped d can't
+		if m.categoryToDelete != nil && m.config.tasksIn(m.categoryToDelete.ID) > 0 {
+			if msg.String() == "D" {
+				return m.deleteCategory("delete")
+			}
+			return m.deleteCategory("reassign")
+		}
+	case "n", "N", "esc":
+		m.taskToDelete = nil
+		m.categoryToDelete = nil
+		m.mode = m.prevMode
+		return m, nil
+	}
+	return m, nil
 }
 
-func (m model) prevCategory() (tea.Model, tea.Cmd) {
-	currentIndex := m.getCategoryIndex()
-	totalTabs := len(m.config.Categories) + 1
-	prevIndex := (currentIndex - 1 + totalTabs) % totalTabs
-	return m.selectCategoryByIndex(prevIndex)
-}
+// reloadConfig re-reads the config from disk. It does nothing when the file
+// hasn't changed since our last save, and asks first when there are
+// unsynced changes in memory.
+func (m model) reloadConfig() (tea.Model, tea.Cmd) {
+	path, err := configPath()
+	if err != nil {
+		m.setStatus("Error reloading config")
+		return m, nil
+	}
 
-func (m model) getCategoryIndex() int {
-	if m.selectedCategoryID == "" {
-		return 0 // "All" is index 0
+	info, err := os.Stat(path)
+	if err != nil {
+		m.setStatus("Error reloading config")
+		return m, nil
 	}
-	for i, cat := range m.config.Categories {
-		if cat.ID == m.selectedCategoryID {
-			return i + 1 // +1 because "All" is at index 0
-		}
+
+	// saveConfig stamps LastUpdate just before writing, so allow a little slack
+	if !info.ModTime().After(m.config.LastUpdate.Add(time.Second)) {
+		m.setStatus("Config on disk is unchanged")
+		return m, nil
 	}
-	return 0
-}
 
-func (m model) selectCategoryByIndex(index int) (tea.Model, tea.Cmd) {
-	m.activeTabIndex = index
-	if index == 0 {
-		m.selectedCategoryID = "" // "All"
-	} else if index-1 < len(m.config.Categories) {
-		m.selectedCategoryID = m.config.Categories[index-1].ID
+	cfg, err := loadConfig()
+	if err != nil {
+		m.setStatus("Error reloading config")
+		return m, nil
 	}
+
+	if m.configChanged {
+		m.pendingReload = cfg
+		m.prevMode = m.mode
+		m.mode = reloadConfirmView
+		return m, nil
+	}
+
+	m.config = cfg
 	m.updateLists()
+	m.setStatus("Config reloaded")
+	if warning := newerConfigWarning(cfg); warning != "" {
+		m.setStatus("⚠ " + warning)
+	}
 	return m, nil
 }
 
-func (m *model) updateLists() {
-	// Helper to find category name
-	getCategoryName := func(categoryID string) string {
-		for _, cat := range m.config.Categories {
-			if cat.ID == categoryID {
-				return cat.Name
+func (m model) handleReloadConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		if m.pendingReload != nil {
+			m.config = m.pendingReload
+			m.updateLists()
+			m.setStatus("Config reloaded")
+			if warning := newerConfigWarning(m.config); warning != "" {
+				m.setStatus("⚠ " + warning)
 			}
 		}
-		return "Unknown"
+		m.pendingReload = nil
+		m.mode = m.prevMode
+		return m, nil
+	case "n", "N", "esc":
+		m.pendingReload = nil
+		m.mode = m.prevMode
+		return m, nil
 	}
+	return m, nil
+}
 
-	// Update active tasks list
-	var activeTasks []TaskItem
-	for _, task := range m.config.Tasks {
-		if !task.Done {
-			// Filter by selected category if not "All"
-			if m.selectedCategoryID != "" && task.CategoryID != m.selectedCategoryID {
-				continue
-			}
-			activeTasks = append(activeTasks, TaskItem{
-				Task:         task,
-				CategoryName: getCategoryName(task.CategoryID),
-			})
+func (m model) handleSnoozeDate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "esc":
+		m.mode = m.prevMode
+		m.dateInput.Blur()
+		return m, nil
+
+	case "enter":
+		// An ISO-8601 duration (PT2H, P3D) snoozes for exactly that long;
+		// anything else is a date
+		until, err := parseDateInput(m.dateInput.Value())
+		if d, durErr := parseISO8601Duration(m.dateInput.Value()); durErr == nil {
+			until, err = time.Now().Add(d), nil
+		} else if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(m.dateInput.Value())), "P") && err != nil {
+			err = durErr
+		}
+		if err != nil {
+			m.dateErr = err.Error()
+			return m, nil
+		}
+		if !until.After(time.Now()) {
+			m.dateErr = "pick a date after today"
+			return m, nil
 		}
+		m.mode = m.prevMode
+		m.dateInput.Blur()
+		return m.snoozeTask(until)
 	}
 
-	// Sort by category name, then by priority
-	sort.Slice(activeTasks, func(i, j int) bool {
-		if activeTasks[i].CategoryName != activeTasks[j].CategoryName {
-			return activeTasks[i].CategoryName < activeTasks[j].CategoryName
+	m.dateErr = ""
+	m.dateInput, cmd = m.dateInput.Update(msg)
+	return m, cmd
+}
+
+// handleCreatedDate backdates the detail view's task. The date may not be
+// in the future or after the task was completed; offsets like 3d count
+// back from today, as in `todobi log --since`.
+func (m model) handleCreatedDate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "esc":
+		m.mode = taskDetailView
+		m.dateInput.Blur()
+		return m, m.notesTextarea.Focus()
+
+	case "enter":
+		now := time.Now()
+		created, err := parseSinceAt(m.dateInput.Value(), now)
+		if err != nil {
+			m.dateErr = err.Error()
+			return m, nil
+		}
+		task := m.editingTask
+		if created.After(now) {
+			m.dateErr = "the created date can't be in the future"
+			return m, nil
+		}
+		if task.Done && created.After(task.CompletedAt) {
+			m.dateErr = "the task was completed on " + task.CompletedAt.Format(m.config.dateFormat()) + "; pick that day or earlier"
+			return m, nil
 		}
-		return activeTasks[i].Priority < activeTasks[j].Priority
-	})
 
-	var activeItems []list.Item
-	for _, task := range activeTasks {
-		activeItems = append(activeItems, task)
+		if !startOfDay(task.CreatedAt).Equal(created) {
+			task.logEvent("backdated", task.CreatedAt.Format("2006-01-02")+" → "+created.Format("2006-01-02"), now)
+			task.CreatedAt = created
+			m.saveConfigAndMarkChanged()
+			m.updateLists()
+			m.setStatus("Created date set to " + created.Format(m.config.dateFormat()))
+		}
+		m.mode = taskDetailView
+		m.dateInput.Blur()
+		return m, m.notesTextarea.Focus()
 	}
-	m.list.SetItems(activeItems)
 
-	// Update completed tasks list (show ALL completed tasks regardless of category filter)
-	var completedTasks []TaskItem
-	for _, task := range m.config.Tasks {
-		if task.Done {
-			completedTasks = append(completedTasks, TaskItem{
-				Task:         task,
-				CategoryName: getCategoryName(task.CategoryID),
-			})
+	m.dateErr = ""
+	m.dateInput, cmd = m.dateInput.Update(msg)
+	return m, cmd
+}
+
+// maxSearchHistory bounds the session's remembered search queries
+const maxSearchHistory = 20
+
+// handleSearch drives the / search input. The list narrows as you type;
+// enter keeps the query, esc drops it, and up/down step through earlier
+// queries like shell history.
+func (m model) handleSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		m.searchInput.Blur()
+		m.searchQuery = ""
+		m.updateLists()
+		return m, nil
+
+	case "enter":
+		m.searching = false
+		m.searchInput.Blur()
+		m.searchQuery = strings.TrimSpace(m.searchInput.Value())
+		if m.searchQuery != "" {
+			m.rememberSearch(m.searchQuery)
 		}
-	}
+		m.updateLists()
+		return m, nil
 
-	// Sort completed tasks by category too
-	sort.Slice(completedTasks, func(i, j int) bool {
-		if completedTasks[i].CategoryName != completedTasks[j].CategoryName {
-			return completedTasks[i].CategoryName < completedTasks[j].CategoryName
+	case "up", "down":
+		if msg.String() == "up" && m.searchRecall > 0 {
+			if m.searchRecall == len(m.searchHistory) {
+				m.searchDraft = m.searchInput.Value()
+			}
+			m.searchRecall--
+		} else if msg.String() == "down" && m.searchRecall < len(m.searchHistory) {
+			m.searchRecall++
+		} else {
+			return m, nil
 		}
-		return completedTasks[i].CompletedAt.After(completedTasks[j].CompletedAt)
-	})
+		if m.searchRecall == len(m.searchHistory) {
+			m.searchInput.SetValue(m.searchDraft)
+		} else {
+			m.searchInput.SetValue(m.searchHistory[m.searchRecall])
+		}
+		m.searchInput.CursorEnd()
+		m.searchQuery = strings.TrimSpace(m.searchInput.Value())
+		m.updateLists()
+		return m, nil
 
-	var completedItems []list.Item
-	for _, task := range completedTasks {
-		completedItems = append(completedItems, task)
+	case "ctrl+c":
+		return m.quit()
 	}
-	m.completedList.SetItems(completedItems)
-}
 
-func (m *model) updateCategoryList() {
-	var items []list.Item
-	for _, cat := range m.config.Categories {
-		items = append(items, cat)
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	if query := strings.TrimSpace(m.searchInput.Value()); query != m.searchQuery {
+		m.searchQuery = query
+		m.updateLists()
 	}
-	m.categoryList.SetItems(items)
+	return m, cmd
 }
 
-func (m model) toggleTask() (tea.Model, tea.Cmd) {
-	var selectedTask Task
-	found := false
-
-	if m.mode == completedView {
-		if item := m.completedList.SelectedItem(); item != nil {
-			selectedTask = item.(TaskItem).Task
-			found = true
-		}
-	} else {
-		if item := m.list.SelectedItem(); item != nil {
-			selectedTask = item.(TaskItem).Task
-			found = true
+// rememberSearch appends query to the session's search history, moving a
+// repeated query to the end and dropping the oldest past maxSearchHistory
+func (m *model) rememberSearch(query string) {
+	for i, q := range m.searchHistory {
+		if q == query {
+			m.searchHistory = append(m.searchHistory[:i], m.searchHistory[i+1:]...)
+			break
 		}
 	}
+	m.searchHistory = append(m.searchHistory, query)
+	if len(m.searchHistory) > maxSearchHistory {
+		m.searchHistory = m.searchHistory[len(m.searchHistory)-maxSearchHistory:]
+	}
+}
 
-	if !found {
+func (m model) handleDedupeConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		removed := removeDuplicates(m.config, m.duplicates)
+		m.saveConfigAndMarkChanged()
+		m.updateLists()
+		m.setStatus(fmt.Sprintf("Removed %d duplicate tasks", removed))
+		m.duplicates = nil
+		m.mode = m.prevMode
+		return m, nil
+	case "n", "N", "esc":
+		m.duplicates = nil
+		m.mode = m.prevMode
 		return m, nil
 	}
+	return m, nil
+}
 
-	// Find and toggle the task in config
-	for i := range m.config.Tasks {
-		if m.config.Tasks[i].ID == selectedTask.ID {
-			m.config.Tasks[i].Done = !m.config.Tasks[i].Done
-			if m.config.Tasks[i].Done {
-				m.config.Tasks[i].CompletedAt = time.Now()
-				m.setStatus("Task completed")
-			} else {
-				m.config.Tasks[i].CompletedAt = time.Time{}
-				m.setStatus("Task reopened")
-			}
-			break
-		}
+func (m model) handleCompleteCategory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		completed := m.config.completeCategory(m.selectedCategoryID)
+		m.saveConfigAndMarkChanged()
+		m.updateLists()
+		m.setStatus(fmt.Sprintf("Completed %d tasks in %s", completed, m.config.categoryName(m.selectedCategoryID)))
+		m.mode = m.prevMode
+		return m, nil
+	case "n", "N", "esc":
+		m.mode = m.prevMode
+		return m, nil
 	}
+	return m, nil
+}
 
-	m.saveConfigAndMarkChanged()
-	m.updateLists()
+func (m model) handleSyncError(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "E", "esc", "q", "enter":
+		m.mode = m.prevMode
+	}
 	return m, nil
 }
 
-func (m model) confirmDelete() (tea.Model, tea.Cmd) {
-	var selectedTask Task
-	found := false
+// recordSyncError keeps a failed sync's message and raw output for the E
+// details view, returning a hint to append to the status line
+func (m *model) recordSyncError(message, details string) string {
+	m.syncError = message
+	m.syncDetails = details
+	if details == "" {
+		return ""
+	}
+	return " (E: details)"
+}
 
-	if m.mode == completedView {
-		if item := m.completedList.SelectedItem(); item != nil {
-			selectedTask = item.(TaskItem).Task
-			found = true
+// configDiff lists task IDs that differ between two configs
+type configDiff struct {
+	onlyLocal  []string
+	onlyRemote []string
+	changed    []string
+}
+
+// diffConfigs compares tasks by ID; a task counts as changed when its
+// content, category, priority, completion, or notes differ
+func diffConfigs(local, remote *Config) configDiff {
+	var diff configDiff
+
+	remoteTasks := make(map[string]Task)
+	for _, task := range remote.Tasks {
+		remoteTasks[task.ID] = task
+	}
+
+	localIDs := make(map[string]bool)
+	for _, task := range local.Tasks {
+		localIDs[task.ID] = true
+		other, ok := remoteTasks[task.ID]
+		if !ok {
+			diff.onlyLocal = append(diff.onlyLocal, task.ID)
+			continue
 		}
-	} else if m.mode == listView {
-		if item := m.list.SelectedItem(); item != nil {
-			selectedTask = item.(TaskItem).Task
-			found = true
+		if task.Content != other.Content || task.CategoryID != other.CategoryID ||
+			task.Priority != other.Priority || task.Done != other.Done || task.Notes != other.Notes ||
+			task.Backlog != other.Backlog {
+			diff.changed = append(diff.changed, task.ID)
 		}
 	}
 
-	if !found {
-		return m, nil
+	for _, task := range remote.Tasks {
+		if !localIDs[task.ID] {
+			diff.onlyRemote = append(diff.onlyRemote, task.ID)
+		}
 	}
-
-	m.taskToDelete = &selectedTask
-	m.prevMode = m.mode
-	m.mode = deleteConfirmView
-	return m, nil
+
+	return diff
 }
 
-func (m model) deleteTask() (tea.Model, tea.Cmd) {
-	if m.taskToDelete == nil {
-		return m, nil
+// resolveConflicts builds the pulled result from local: tasks in takeRemote
+// use the remote version, remote-only tasks are added along with any
+// categories they need, and local settings are kept
+func resolveConflicts(local, remote *Config, takeRemote map[string]bool) *Config {
+	remoteTasks := make(map[string]Task)
+	for _, task := range remote.Tasks {
+		remoteTasks[task.ID] = task
 	}
 
-	// Find and delete the task
-	for i := range m.config.Tasks {
-		if m.config.Tasks[i].ID == m.taskToDelete.ID {
-			m.config.Tasks = append(m.config.Tasks[:i], m.config.Tasks[i+1:]...)
-			break
+	merged := *local
+	merged.LastUpdate = time.Now()
+	merged.Tasks = nil
+	localIDs := make(map[string]bool)
+	for _, task := range local.Tasks {
+		localIDs[task.ID] = true
+		if takeRemote[task.ID] {
+			task = remoteTasks[task.ID]
+		}
+		merged.Tasks = append(merged.Tasks, task)
+	}
+	for _, task := range remote.Tasks {
+		if !localIDs[task.ID] {
+			merged.Tasks = append(merged.Tasks, task)
 		}
 	}
 
-	m.saveConfigAndMarkChanged()
-	m.updateLists()
-	m.setStatus("Task deleted")
-	m.taskToDelete = nil
-	m.mode = m.prevMode
-	return m, nil
+	merged.Categories = append([]Category(nil), local.Categories...)
+	have := make(map[string]bool)
+	for _, cat := range local.Categories {
+		have[cat.ID] = true
+	}
+	for _, cat := range remote.Categories {
+		if !have[cat.ID] {
+			merged.Categories = append(merged.Categories, cat)
+		}
+	}
+
+	return &merged
 }
 
-func (m model) handleDeleteConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// handleResolveConflicts steps through conflicting tasks; l or r picks a
+// side and moves on, and the last pick applies the result
+func (m model) handleResolveConflicts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
-	case "y", "Y":
-		if m.taskToDelete != nil {
-			return m.deleteTask()
-		} else if m.categoryToDelete != nil {
-			return m.deleteCategory()
+	case "l", "L", "r", "R":
+		id := m.conflictIDs[m.conflictIndex]
+		m.takeRemote[id] = strings.ToLower(msg.String()) == "r"
+		if m.conflictIndex < len(m.conflictIDs)-1 {
+			m.conflictIndex++
+			return m, nil
 		}
-	case "n", "N", "esc":
-		m.taskToDelete = nil
-		m.categoryToDelete = nil
+
+		remoteCount := 0
+		for _, remote := range m.takeRemote {
+			if remote {
+				remoteCount++
+			}
+		}
+		m.config = resolveConflicts(m.config, m.remoteConfig, m.takeRemote)
+		m.saveConfigAndMarkChanged()
+		m.updateLists()
+		m.setStatus(fmt.Sprintf("Resolved %d conflicts (%d from remote)", len(m.conflictIDs), remoteCount))
+		m.remoteConfig = nil
+		m.conflictIDs = nil
+		m.takeRemote = nil
 		m.mode = m.prevMode
 		return m, nil
+	case "left", "h", "backspace":
+		if m.conflictIndex > 0 {
+			m.conflictIndex--
+		}
+	case "esc":
+		m.conflictIDs = nil
+		m.takeRemote = nil
+		m.mode = pullConfirmView
 	}
 	return m, nil
 }
@@ -987,8 +6555,7 @@ func (m model) handleSyncConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "y", "Y":
 		m.syncInProgress = true
 		m.setStatus("Syncing to GitHub...")
-		// Return both the sync command AND the spinner tick to start animation
-		return m, tea.Batch(syncToGitHubCmd(), m.spinner.Tick)
+		return m, m.startSync()
 	case "n", "N", "esc":
 		m.mode = m.prevMode
 		return m, nil
@@ -997,6 +6564,10 @@ func (m model) handleSyncConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handlePullConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.pullSelecting {
+		return m.handlePullSelect(msg)
+	}
+
 	switch msg.String() {
 	case "l", "L":
 		// Keep local - discard remote
@@ -1008,6 +6579,7 @@ func (m model) handlePullConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Use remote - overwrite local
 		if m.remoteConfig != nil {
 			m.config = m.remoteConfig
+			m.config.GitHubSetupComplete = true // the pull just worked
 			m.saveConfigAndMarkChanged()
 			m.updateLists()
 			m.remoteConfig = nil
@@ -1020,6 +6592,7 @@ func (m model) handlePullConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Merge: combine tasks and categories
 		if m.remoteConfig != nil {
 			m.config = mergeConfigs(m.config, m.remoteConfig)
+			m.config.GitHubSetupComplete = true // the pull just worked
 			m.saveConfigAndMarkChanged()
 			m.updateLists()
 			m.remoteConfig = nil
@@ -1028,6 +6601,25 @@ func (m model) handlePullConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.mode = m.prevMode
 		return m, nil
+	case "c", "C":
+		// Resolve each task that changed on both sides
+		if m.remoteConfig != nil {
+			if ids := diffConfigs(m.config, m.remoteConfig).changed; len(ids) > 0 {
+				m.conflictIDs = ids
+				m.conflictIndex = 0
+				m.takeRemote = make(map[string]bool)
+				m.mode = resolveConflictsView
+			}
+		}
+		return m, nil
+	case "s", "S":
+		// Selective merge: pick which remote categories to bring in
+		if m.remoteConfig != nil && len(m.remoteConfig.Categories) > 0 {
+			m.pullSelecting = true
+			m.pullCursor = 0
+			m.pullSelected = make(map[string]bool)
+		}
+		return m, nil
 	case "esc":
 		m.remoteConfig = nil
 		m.mode = m.prevMode
@@ -1036,11 +6628,118 @@ func (m model) handlePullConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handlePullSelect drives the category checklist for a selective merge
+func (m model) handlePullSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	categories := m.remoteConfig.Categories
+
+	switch msg.String() {
+	case "up", "k":
+		if m.pullCursor > 0 {
+			m.pullCursor--
+		}
+	case "down", "j":
+		if m.pullCursor < len(categories)-1 {
+			m.pullCursor++
+		}
+	case " ", "space", "x":
+		id := categories[m.pullCursor].ID
+		m.pullSelected[id] = !m.pullSelected[id]
+	case "enter":
+		var ids []string
+		for _, cat := range categories {
+			if m.pullSelected[cat.ID] {
+				ids = append(ids, cat.ID)
+			}
+		}
+		if len(ids) == 0 {
+			m.setStatus("No categories selected")
+			return m, nil
+		}
+		m.config = mergeSelected(m.config, m.remoteConfig, ids)
+		m.saveConfigAndMarkChanged()
+		m.updateLists()
+		m.remoteConfig = nil
+		m.pullSelecting = false
+		m.setStatus(fmt.Sprintf("Merged %d categories from remote", len(ids)))
+		m.mode = m.prevMode
+	case "esc":
+		// Back to the L/R/M/S choice
+		m.pullSelecting = false
+	}
+	return m, nil
+}
+
+// mergeSelected brings remote changes into local for the given categories
+// only. Remote wins for categories and tasks in the selection; everything
+// else, including local settings, is left untouched.
+func mergeSelected(local, remote *Config, categoryIDs []string) *Config {
+	selected := make(map[string]bool)
+	for _, id := range categoryIDs {
+		selected[id] = true
+	}
+
+	merged := *local
+	merged.LastUpdate = time.Now()
+	merged.Categories = nil
+	merged.Tasks = nil
+
+	remoteCats := make(map[string]Category)
+	for _, cat := range remote.Categories {
+		remoteCats[cat.ID] = cat
+	}
+	seen := make(map[string]bool)
+	for _, cat := range local.Categories {
+		if rc, ok := remoteCats[cat.ID]; ok && selected[cat.ID] {
+			cat = rc
+		}
+		merged.Categories = append(merged.Categories, cat)
+		seen[cat.ID] = true
+	}
+	for _, cat := range remote.Categories {
+		if selected[cat.ID] && !seen[cat.ID] {
+			merged.Categories = append(merged.Categories, cat)
+		}
+	}
+
+	// Remote tasks that now live in a selected category replace local copies
+	incoming := make(map[string]Task)
+	for _, task := range remote.Tasks {
+		if selected[task.CategoryID] {
+			incoming[task.ID] = task
+		}
+	}
+	for _, task := range local.Tasks {
+		if rt, ok := incoming[task.ID]; ok {
+			task = rt
+			delete(incoming, task.ID)
+		}
+		merged.Tasks = append(merged.Tasks, task)
+	}
+	for _, task := range remote.Tasks {
+		if t, ok := incoming[task.ID]; ok {
+			merged.Tasks = append(merged.Tasks, t)
+		}
+	}
+
+	return &merged
+}
+
 // mergeConfigs combines local and remote configs intelligently
 func mergeConfigs(local, remote *Config) *Config {
-	merged := &Config{
-		Version:    local.Version,
-		LastUpdate: time.Now(),
+	// Settings come from local; only categories and tasks are merged
+	merged := *local
+	merged.LastUpdate = time.Now()
+	merged.Categories = nil
+	merged.Tasks = nil
+
+	// Keep settings only some todobi version knows, remote winning. The
+	// map is copied so local's own is left alone.
+	merged.unknown = make(unknownFields)
+	for name, value := range local.unknown {
+		merged.unknown[name] = value
+	}
+	for name, value := range remote.unknown {
+		merged.unknown[name] = value
 	}
 
 	// Merge categories by ID
@@ -1075,254 +6774,580 @@ func mergeConfigs(local, remote *Config) *Config {
 		merged.Tasks = append(merged.Tasks, task)
 	}
 
-	return merged
+	return &merged
+}
+
+// tasksIn counts every task in the category, completed ones included
+func (c *Config) tasksIn(categoryID string) int {
+	count := 0
+	for _, task := range c.Tasks {
+		if task.CategoryID == categoryID {
+			count++
+		}
+	}
+	return count
 }
 
-func (m model) deleteCategory() (tea.Model, tea.Cmd) {
+// deleteCategory deletes categoryToDelete. how says what happens to any
+// tasks still in it: refuse leaves everything alone, reassign moves them
+// to Uncategorized, and delete removes them with the category.
+func (m model) deleteCategory(how string) (tea.Model, tea.Cmd) {
 	if m.categoryToDelete == nil {
 		return m, nil
 	}
+	cat := *m.categoryToDelete
+	m.categoryToDelete = nil
+	m.mode = m.prevMode
 
-	// Check if category has tasks
-	tasksInCategory := 0
-	for _, task := range m.config.Tasks {
-		if task.CategoryID == m.categoryToDelete.ID {
-			tasksInCategory++
+	status := "Category deleted"
+	tasksInCategory := m.config.tasksIn(cat.ID)
+	switch {
+	case tasksInCategory == 0:
+	case how == "reassign":
+		if strings.EqualFold(cat.Name, uncategorizedName) {
+			m.setStatus("Cannot reassign: this is the " + uncategorizedName + " category")
+			return m, nil
 		}
-	}
-
-	if tasksInCategory > 0 {
+		moved := m.config.mergeCategories(cat.ID, m.config.uncategorizedID())
+		status = fmt.Sprintf("Category deleted - %d tasks moved to %s", moved, uncategorizedName)
+	case how == "delete":
+		kept := m.config.Tasks[:0]
+		for _, task := range m.config.Tasks {
+			if task.CategoryID != cat.ID {
+				kept = append(kept, task)
+			}
+		}
+		m.config.Tasks = kept
+		status = fmt.Sprintf("Category and its %d tasks deleted", tasksInCategory)
+	default:
 		m.setStatus(fmt.Sprintf("Cannot delete: %d tasks in category", tasksInCategory))
-		m.categoryToDelete = nil
-		m.mode = m.prevMode
 		return m, nil
 	}
 
-	// Delete the category
+	// Delete the category (reassigning already has)
 	for i := range m.config.Categories {
-		if m.config.Categories[i].ID == m.categoryToDelete.ID {
+		if m.config.Categories[i].ID == cat.ID {
 			m.config.Categories = append(m.config.Categories[:i], m.config.Categories[i+1:]...)
 			break
 		}
 	}
+	if m.selectedCategoryID == cat.ID {
+		m.selectedCategoryID = ""
+		m.activeTabIndex = 0
+	}
 
 	m.saveConfigAndMarkChanged()
 	m.updateCategoryList()
-	m.setStatus("Category deleted")
-	m.categoryToDelete = nil
-	m.mode = m.prevMode
+	m.updateLists()
+	m.setStatus(status)
 	return m, nil
 }
 
-// syncToGitHubCmd returns a tea.Cmd that performs the GitHub sync asynchronously
-func syncToGitHubCmd() tea.Cmd {
-	return func() tea.Msg {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return syncResultMsg{success: false, error: err.Error()}
+// pendingIn counts the category's unfinished tasks, snoozed ones included
+// and the backlog left out
+func (c *Config) pendingIn(categoryID string) int {
+	count := 0
+	for _, task := range c.Tasks {
+		if task.CategoryID == categoryID && !task.Done && !task.Backlog {
+			count++
 		}
+	}
+	return count
+}
 
-		configPath := filepath.Join(home, configFileName)
-		repoName := "todobi-sync"
-
-		// Check if gh CLI is installed
-		if err := exec.Command("gh", "--version").Run(); err != nil {
-			return syncResultMsg{success: false, error: "gh CLI not installed. Install from https://cli.github.com"}
+// completeCategory marks every pending task in the category done and
+// returns how many it finished
+func (c *Config) completeCategory(categoryID string) int {
+	now := time.Now()
+	completed := 0
+	for i := range c.Tasks {
+		if c.Tasks[i].CategoryID == categoryID && !c.Tasks[i].Done && !c.Tasks[i].Backlog {
+			c.Tasks[i].setState(StateDone, now)
+			completed++
 		}
+	}
+	return completed
+}
 
-		// Check gh auth status
-		authCheckCmd := exec.Command("gh", "auth", "status")
-		if err := authCheckCmd.Run(); err != nil {
-			return syncResultMsg{success: false, error: "gh CLI not authenticated. Run: gh auth login"}
+// mergeCategories moves every task from srcID into dstID, points saved
+// filters at dstID, and deletes srcID. It returns how many tasks moved.
+func (c *Config) mergeCategories(srcID, dstID string) int {
+	srcName, dstName := c.categoryName(srcID), c.categoryName(dstID)
+	now := time.Now()
+
+	moved := 0
+	for i := range c.Tasks {
+		if c.Tasks[i].CategoryID == srcID {
+			c.Tasks[i].CategoryID = dstID
+			c.Tasks[i].logEvent("recategorized", srcName+" → "+dstName, now)
+			moved++
+		}
+	}
+	for i := range c.SavedFilters {
+		if c.SavedFilters[i].CategoryID == srcID {
+			c.SavedFilters[i].CategoryID = dstID
+		}
+	}
+	// Keep the source's notes below the target's
+	var srcNotes string
+	for _, cat := range c.Categories {
+		if cat.ID == srcID {
+			srcNotes = cat.Notes
+		}
+	}
+	for i := range c.Categories {
+		if c.Categories[i].ID == dstID && srcNotes != "" {
+			c.Categories[i].Notes = strings.TrimSpace(c.Categories[i].Notes + "\n\n" + srcNotes)
+		}
+	}
+	for i := range c.Categories {
+		if c.Categories[i].ID == srcID {
+			c.Categories = append(c.Categories[:i], c.Categories[i+1:]...)
+			break
 		}
+	}
+	return moved
+}
 
-		// Get current user for HTTPS URL construction
-		whoamiCmd := exec.Command("gh", "api", "user", "-q", ".login")
-		usernameBytes, err := whoamiCmd.Output()
-		if err != nil {
-			return syncResultMsg{success: false, error: "Error getting GitHub username: " + err.Error()}
+// mergeTargets lists the categories the merge source can be folded into
+func (m model) mergeTargets() []Category {
+	var targets []Category
+	for _, cat := range m.config.Categories {
+		if m.mergeSource != nil && cat.ID != m.mergeSource.ID {
+			targets = append(targets, cat)
 		}
-		githubUser := strings.TrimSpace(string(usernameBytes))
+	}
+	return targets
+}
 
-		// Create temp directory for git operations
-		tmpDir := filepath.Join(os.TempDir(), "todobi-sync-tmp")
-		os.RemoveAll(tmpDir)
-		if err := os.MkdirAll(tmpDir, 0755); err != nil {
-			return syncResultMsg{success: false, error: "Failed to create temp directory: " + err.Error()}
+func (m model) handleCategoryMerge(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	targets := m.mergeTargets()
+	if len(targets) == 0 {
+		m.mode = categoryListView
+		return m, nil
+	}
+
+	if m.mergeConfirm {
+		switch msg.String() {
+		case "y", "Y":
+			dst := targets[m.mergeCursor]
+			moved := m.config.mergeCategories(m.mergeSource.ID, dst.ID)
+			if m.selectedCategoryID == m.mergeSource.ID {
+				m.selectedCategoryID = ""
+				m.activeTabIndex = 0
+			}
+			m.saveConfigAndMarkChanged()
+			m.updateCategoryList()
+			m.updateLists()
+			m.setStatus(fmt.Sprintf("Merged %s into %s (%d tasks moved)", m.mergeSource.Name, dst.Name, moved))
+			m.mergeSource = nil
+			m.mode = categoryListView
+		case "n", "N", "esc":
+			m.mergeConfirm = false
 		}
-		defer os.RemoveAll(tmpDir)
+		return m, nil
+	}
 
-		// Check if repo exists
-		checkCmd := exec.Command("gh", "repo", "view", repoName, "--json", "name")
-		repoExists := checkCmd.Run() == nil
+	switch msg.String() {
+	case "up", "k":
+		if m.mergeCursor > 0 {
+			m.mergeCursor--
+		}
+	case "down", "j":
+		if m.mergeCursor < len(targets)-1 {
+			m.mergeCursor++
+		}
+	case "enter":
+		m.mergeConfirm = true
+	case "esc":
+		m.mergeSource = nil
+		m.mode = categoryListView
+	}
+	return m, nil
+}
 
-		repoURL := fmt.Sprintf("https://github.com/%s/%s.git", githubUser, repoName)
+func (m model) renderCategoryMerge() string {
+	if m.mergeSource == nil {
+		return ""
+	}
 
-		if !repoExists {
-			// Repo doesn't exist, create it
-			createCmd := exec.Command("gh", "repo", "create", repoName, "--private", "--clone=false")
-			createCmd.Stdin = nil
-			output, err := createCmd.CombinedOutput()
-			if err != nil {
-				return syncResultMsg{success: false, error: fmt.Sprintf("Error creating repo: %s - %s", err.Error(), string(output))}
-			}
+	var output strings.Builder
 
-			// Initialize new repo locally
-			initCmd := exec.Command("git", "init")
-			initCmd.Dir = tmpDir
-			if err := initCmd.Run(); err != nil {
-				return syncResultMsg{success: false, error: "Error initializing git: " + err.Error()}
-			}
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent)
+	infoStyle := lipgloss.NewStyle().Foreground(colorText)
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
 
-			// Configure git credential helper to use gh
-			credCmd := exec.Command("git", "config", "credential.helper", "")
-			credCmd.Dir = tmpDir
-			credCmd.Run()
+	targets := m.mergeTargets()
 
-			credCmd = exec.Command("git", "config", "--add", "credential.helper", "!gh auth git-credential")
-			credCmd.Dir = tmpDir
-			if err := credCmd.Run(); err != nil {
-				return syncResultMsg{success: false, error: "Error configuring credential helper: " + err.Error()}
-			}
+	output.WriteString(titleStyle.Render("Merge \"" + m.mergeSource.Name + "\" into..."))
+	output.WriteString("\n\n")
 
-			// Add remote
-			remoteCmd := exec.Command("git", "remote", "add", "origin", repoURL)
-			remoteCmd.Dir = tmpDir
-			if err := remoteCmd.Run(); err != nil {
-				return syncResultMsg{success: false, error: "Error adding remote: " + err.Error()}
-			}
+	for i, cat := range targets {
+		if i == m.mergeCursor {
+			output.WriteString(titleStyle.Render("> " + cat.Name))
 		} else {
-			// Clone existing repo using HTTPS
-			cloneCmd := exec.Command("git", "clone", repoURL, tmpDir)
-			cloneCmd.Stdin = nil
-			cloneCmd.Env = append(os.Environ(),
-				"GIT_TERMINAL_PROMPT=0",
-				"GIT_CONFIG_COUNT=1",
-				"GIT_CONFIG_KEY_0=credential.helper",
-				"GIT_CONFIG_VALUE_0=!gh auth git-credential",
-			)
-			output, err := cloneCmd.CombinedOutput()
-			if err != nil {
-				return syncResultMsg{success: false, error: fmt.Sprintf("Error cloning repo: %s - %s", err.Error(), string(output))}
+			output.WriteString(infoStyle.Render("  " + cat.Name))
+		}
+		output.WriteString("\n")
+	}
+	output.WriteString("\n")
+
+	if m.mergeConfirm {
+		count := 0
+		for _, task := range m.config.Tasks {
+			if task.CategoryID == m.mergeSource.ID {
+				count++
 			}
 		}
+		warningStyle := lipgloss.NewStyle().Foreground(colorWarning).Bold(true)
+		output.WriteString(warningStyle.Render(fmt.Sprintf("Move %d tasks into %s and delete %s?", count, targets[m.mergeCursor].Name, m.mergeSource.Name)))
+		output.WriteString("\n\n")
+		output.WriteString(helpStyle.Render("y: merge | n/esc: back"))
+	} else {
+		output.WriteString(helpStyle.Render("↑/↓: pick destination | enter: continue | esc: cancel"))
+	}
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+// syncTarget identifies where a workspace's config syncs to. It is
+// resolved before the sync starts so switching workspaces mid-sync is safe.
+type syncTarget struct {
+	configPath string
+	repoName   string
+	owner      string // empty means the authenticated gh user
+	visibility string
+}
+
+func currentSyncTarget(cfg *Config) (syncTarget, error) {
+	path, err := configPath()
+	if err != nil {
+		return syncTarget{}, err
+	}
+	return syncTarget{
+		configPath: path,
+		repoName:   syncRepoName(),
+		owner:      cfg.SyncOwner,
+		visibility: cfg.syncVisibility(),
+	}, nil
+}
+
+// repoPath qualifies the sync repo as owner/name, defaulting to the gh user
+func (t syncTarget) repoPath(githubUser string) string {
+	if t.owner != "" {
+		return t.owner + "/" + t.repoName
+	}
+	return githubUser + "/" + t.repoName
+}
+
+// startSync launches the GitHub push with a timeout that can also be
+// cancelled from the UI
+func (m *model) startSync() tea.Cmd {
+	target, err := currentSyncTarget(m.config)
+	if err == nil {
+		err = m.config.checkSyncVisibility()
+	}
+	if err != nil {
+		return func() tea.Msg { return syncResultMsg{success: false, error: err.Error()} }
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	m.syncCancel = cancel
+	// Return both the sync command AND the spinner tick to start animation
+	return tea.Batch(syncToGitHubCmd(ctx, target), m.spinner.Tick)
+}
+
+// startPull launches the GitHub pull with the same timeout as startSync
+func (m *model) startPull() tea.Cmd {
+	target, err := currentSyncTarget(m.config)
+	if err != nil {
+		return func() tea.Msg { return pullResultMsg{success: false, error: err.Error()} }
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	m.syncCancel = cancel
+	return tea.Batch(pullFromGitHubCmd(ctx, target, m.config), m.spinner.Tick)
+}
+
+// cancelSync aborts any in-flight sync or pull
+func (m *model) cancelSync() {
+	if m.syncCancel != nil {
+		m.syncCancel()
+		m.syncCancel = nil
+	}
+}
+
+// syncToGitHubCmd returns a tea.Cmd that performs the GitHub sync asynchronously
+func syncToGitHubCmd(ctx context.Context, target syncTarget) tea.Cmd {
+	return func() tea.Msg {
+		msg := syncToGitHub(ctx, target)
+		if !msg.success && ctx.Err() == context.DeadlineExceeded {
+			msg.error = fmt.Sprintf("timed out after %s", syncTimeout)
+		} else if !msg.success {
+			msg.error, msg.details = explainGitHubError(msg.error, msg.details)
+		}
+		return msg
+	}
+}
+
+// syncToGitHub pushes the local config to the workspace's sync repo
+func syncToGitHub(ctx context.Context, target syncTarget) syncResultMsg {
+	localPath := target.configPath
+
+	// Check if gh CLI is installed
+	if err := exec.CommandContext(ctx, "gh", "--version").Run(); err != nil {
+		return syncResultMsg{success: false, error: "gh CLI not installed. Install from https://cli.github.com"}
+	}
+
+	// Check gh auth status
+	authCheckCmd := exec.CommandContext(ctx, "gh", "auth", "status")
+	if output, err := authCheckCmd.CombinedOutput(); err != nil {
+		return syncResultMsg{success: false, error: "gh CLI not authenticated. Run: gh auth login", details: string(output)}
+	}
+
+	// Get current user for HTTPS URL construction
+	whoamiCmd := exec.CommandContext(ctx, "gh", "api", "user", "-q", ".login")
+	usernameBytes, err := whoamiCmd.Output()
+	if err != nil {
+		return syncResultMsg{success: false, error: "Error getting GitHub username: " + err.Error(), details: stderrOf(err)}
+	}
+	repoName := target.repoPath(strings.TrimSpace(string(usernameBytes)))
+
+	// Create temp directory for git operations
+	tmpDir := filepath.Join(os.TempDir(), "todobi-sync-tmp")
+	os.RemoveAll(tmpDir)
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return syncResultMsg{success: false, error: "Failed to create temp directory: " + err.Error()}
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Check if repo exists
+	checkCmd := exec.CommandContext(ctx, "gh", "repo", "view", repoName, "--json", "name")
+	repoExists := checkCmd.Run() == nil
+
+	repoURL := fmt.Sprintf("https://github.com/%s.git", repoName)
+
+	if !repoExists {
+		// Repo doesn't exist, create it
+		createCmd := exec.CommandContext(ctx, "gh", "repo", "create", repoName, "--"+target.visibility, "--clone=false")
+		createCmd.Stdin = nil
+		output, err := createCmd.CombinedOutput()
+		if err != nil {
+			return syncResultMsg{success: false, error: "Error creating repo: " + err.Error(), details: string(output)}
+		}
+
+		// Initialize new repo locally
+		initCmd := exec.CommandContext(ctx, "git", "init")
+		initCmd.Dir = tmpDir
+		if err := initCmd.Run(); err != nil {
+			return syncResultMsg{success: false, error: "Error initializing git: " + err.Error()}
+		}
+
+		// Configure git credential helper to use gh
+		credCmd := exec.CommandContext(ctx, "git", "config", "credential.helper", "")
+		credCmd.Dir = tmpDir
+		credCmd.Run()
+
+		credCmd = exec.CommandContext(ctx, "git", "config", "--add", "credential.helper", "!gh auth git-credential")
+		credCmd.Dir = tmpDir
+		if err := credCmd.Run(); err != nil {
+			return syncResultMsg{success: false, error: "Error configuring credential helper: " + err.Error()}
+		}
+
+		// Add remote
+		remoteCmd := exec.CommandContext(ctx, "git", "remote", "add", "origin", repoURL)
+		remoteCmd.Dir = tmpDir
+		if err := remoteCmd.Run(); err != nil {
+			return syncResultMsg{success: false, error: "Error adding remote: " + err.Error()}
+		}
+	} else {
+		// Clone existing repo using HTTPS
+		cloneCmd := exec.CommandContext(ctx, "git", "clone", repoURL, tmpDir)
+		cloneCmd.Stdin = nil
+		cloneCmd.Env = append(os.Environ(),
+			"GIT_TERMINAL_PROMPT=0",
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=credential.helper",
+			"GIT_CONFIG_VALUE_0=!gh auth git-credential",
+		)
+		output, err := cloneCmd.CombinedOutput()
+		if err != nil {
+			return syncResultMsg{success: false, error: "Error cloning repo: " + err.Error(), details: string(output)}
+		}
+	}
+
+	// Copy config file to repo
+	destPath := filepath.Join(tmpDir, ".todobi.conf")
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return syncResultMsg{success: false, error: "Error reading config: " + err.Error()}
+	}
+
+	if err := os.WriteFile(destPath, data, 0644); err != nil {
+		return syncResultMsg{success: false, error: "Error writing config to repo: " + err.Error()}
+	}
 
-		// Copy config file to repo
-		destPath := filepath.Join(tmpDir, ".todobi.conf")
-		data, err := os.ReadFile(configPath)
-		if err != nil {
-			return syncResultMsg{success: false, error: "Error reading config: " + err.Error()}
-		}
+	// Git add, commit, push
+	addCmd := exec.CommandContext(ctx, "git", "add", ".todobi.conf")
+	addCmd.Dir = tmpDir
+	if err := addCmd.Run(); err != nil {
+		return syncResultMsg{success: false, error: "Error adding file: " + err.Error()}
+	}
 
-		if err := os.WriteFile(destPath, data, 0644); err != nil {
-			return syncResultMsg{success: false, error: "Error writing config to repo: " + err.Error()}
-		}
+	commitCmd := exec.CommandContext(ctx, "git", "commit", "-m", fmt.Sprintf("Update tasks - %s", time.Now().Format("2006-01-02 15:04:05")))
+	commitCmd.Dir = tmpDir
+	commitCmd.Run() // Ignore error if nothing to commit
 
-		// Git add, commit, push
-		addCmd := exec.Command("git", "add", ".todobi.conf")
-		addCmd.Dir = tmpDir
-		if err := addCmd.Run(); err != nil {
-			return syncResultMsg{success: false, error: "Error adding file: " + err.Error()}
-		}
+	pushCmd := exec.CommandContext(ctx, "git", "push")
+	pushCmd.Dir = tmpDir
+	if output, err := pushCmd.CombinedOutput(); err != nil {
+		return syncResultMsg{success: false, error: "Error pushing to GitHub: " + err.Error(), details: string(output)}
+	}
+
+	return syncResultMsg{success: true}
+}
 
-		commitCmd := exec.Command("git", "commit", "-m", fmt.Sprintf("Update tasks - %s", time.Now().Format("2006-01-02 15:04:05")))
-		commitCmd.Dir = tmpDir
-		commitCmd.Run() // Ignore error if nothing to commit
+// githubErrorHints maps fragments of gh/git output to what the user can do
+// about them; the first match wins
+var githubErrorHints = []struct {
+	patterns []string
+	hint     string
+}{
+	{[]string{"rate limit", "abuse detection"}, "GitHub rate limit hit - wait a few minutes, then sync again"},
+	{[]string{"token expired", "token has expired", "bad credentials", "authentication failed", "invalid username or password", "failed to log in", "http 401"},
+		"GitHub login expired - run: gh auth refresh (or gh auth login), then sync again"},
+	{[]string{"http 403", "permission denied", "must have admin rights", "resource not accessible"},
+		"GitHub refused access - check sync_owner and your permissions on the repo"},
+	{[]string{"could not resolve host", "network is unreachable", "connection timed out", "connection refused"},
+		"Can't reach GitHub - check your network connection"},
+}
 
-		pushCmd := exec.Command("git", "push")
-		pushCmd.Dir = tmpDir
-		if err := pushCmd.Run(); err != nil {
-			return syncResultMsg{success: false, error: "Error pushing to GitHub: " + err.Error()}
+// explainGitHubError swaps a raw sync error for an actionable hint when the
+// error or the command output matches a known failure, keeping the raw
+// text as details
+func explainGitHubError(message, details string) (string, string) {
+	raw := strings.TrimSpace(message + "\n" + strings.TrimSpace(details))
+	lower := strings.ToLower(raw)
+	for _, h := range githubErrorHints {
+		for _, p := range h.patterns {
+			if strings.Contains(lower, p) {
+				return h.hint, raw
+			}
 		}
+	}
+	return message, strings.TrimSpace(details)
+}
 
-		return syncResultMsg{success: true}
+// stderrOf returns what a failed command wrote to stderr, when captured
+func stderrOf(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return string(exitErr.Stderr)
 	}
+	return ""
 }
 
 // pullFromGitHubCmd returns a tea.Cmd that pulls config from GitHub asynchronously
-func pullFromGitHubCmd(localConfig *Config) tea.Cmd {
+func pullFromGitHubCmd(ctx context.Context, target syncTarget, localConfig *Config) tea.Cmd {
 	return func() tea.Msg {
-		repoName := "todobi-sync"
-
-		// Check if gh CLI is installed
-		if err := exec.Command("gh", "--version").Run(); err != nil {
-			return pullResultMsg{success: false, error: "gh CLI not installed. Install from https://cli.github.com"}
+		msg := pullFromGitHub(ctx, target, localConfig)
+		if !msg.success && ctx.Err() == context.DeadlineExceeded {
+			msg.error = fmt.Sprintf("timed out after %s", syncTimeout)
+		} else if !msg.success {
+			msg.error, msg.details = explainGitHubError(msg.error, msg.details)
 		}
+		return msg
+	}
+}
 
-		// Check gh auth status
-		authCheckCmd := exec.Command("gh", "auth", "status")
-		if err := authCheckCmd.Run(); err != nil {
-			return pullResultMsg{success: false, error: "gh CLI not authenticated. Run: gh auth login"}
-		}
+// pullFromGitHub fetches the remote config and checks it against localConfig
+func pullFromGitHub(ctx context.Context, target syncTarget, localConfig *Config) pullResultMsg {
 
-		// Get current user for HTTPS URL construction
-		whoamiCmd := exec.Command("gh", "api", "user", "-q", ".login")
-		usernameBytes, err := whoamiCmd.Output()
-		if err != nil {
-			return pullResultMsg{success: false, error: "Error getting GitHub username: " + err.Error()}
-		}
-		githubUser := strings.TrimSpace(string(usernameBytes))
+	// Check if gh CLI is installed
+	if err := exec.CommandContext(ctx, "gh", "--version").Run(); err != nil {
+		return pullResultMsg{success: false, error: "gh CLI not installed. Install from https://cli.github.com"}
+	}
 
-		// Check if repo exists
-		checkCmd := exec.Command("gh", "repo", "view", repoName, "--json", "name")
-		if checkCmd.Run() != nil {
-			return pullResultMsg{success: false, error: "Remote repo 'todobi-sync' does not exist. Push to GitHub first with 'G'"}
-		}
+	// Check gh auth status
+	authCheckCmd := exec.CommandContext(ctx, "gh", "auth", "status")
+	if output, err := authCheckCmd.CombinedOutput(); err != nil {
+		return pullResultMsg{success: false, error: "gh CLI not authenticated. Run: gh auth login", details: string(output)}
+	}
 
-		// Create temp directory for git operations
-		tmpDir := filepath.Join(os.TempDir(), "todobi-pull-tmp")
-		os.RemoveAll(tmpDir)
-		if err := os.MkdirAll(tmpDir, 0755); err != nil {
-			return pullResultMsg{success: false, error: "Failed to create temp directory: " + err.Error()}
-		}
-		defer os.RemoveAll(tmpDir)
+	// Get current user for HTTPS URL construction
+	whoamiCmd := exec.CommandContext(ctx, "gh", "api", "user", "-q", ".login")
+	usernameBytes, err := whoamiCmd.Output()
+	if err != nil {
+		return pullResultMsg{success: false, error: "Error getting GitHub username: " + err.Error(), details: stderrOf(err)}
+	}
+	repoName := target.repoPath(strings.TrimSpace(string(usernameBytes)))
 
-		// Clone the repo using HTTPS with gh credential helper
-		repoURL := fmt.Sprintf("https://github.com/%s/%s.git", githubUser, repoName)
-		cloneCmd := exec.Command("git", "clone", repoURL, tmpDir)
-		cloneCmd.Stdin = nil
-		cloneCmd.Env = append(os.Environ(),
-			"GIT_TERMINAL_PROMPT=0",
-			"GIT_CONFIG_COUNT=1",
-			"GIT_CONFIG_KEY_0=credential.helper",
-			"GIT_CONFIG_VALUE_0=!gh auth git-credential",
-		)
-		output, err := cloneCmd.CombinedOutput()
-		if err != nil {
-			return pullResultMsg{success: false, error: fmt.Sprintf("Error cloning repo: %s - %s", err.Error(), string(output))}
-		}
+	// Check if repo exists
+	checkCmd := exec.CommandContext(ctx, "gh", "repo", "view", repoName, "--json", "name")
+	if checkCmd.Run() != nil {
+		return pullResultMsg{success: false, noRemote: true, error: fmt.Sprintf("Remote repo '%s' does not exist. Push to GitHub first with 'G'", repoName)}
+	}
 
-		// Read the remote config
-		remotePath := filepath.Join(tmpDir, ".todobi.conf")
-		data, err := os.ReadFile(remotePath)
-		if err != nil {
-			return pullResultMsg{success: false, error: "Error reading remote config: " + err.Error()}
-		}
+	// Create temp directory for git operations
+	tmpDir := filepath.Join(os.TempDir(), "todobi-pull-tmp")
+	os.RemoveAll(tmpDir)
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return pullResultMsg{success: false, error: "Failed to create temp directory: " + err.Error()}
+	}
+	defer os.RemoveAll(tmpDir)
 
-		var remoteConfig Config
-		if err := json.Unmarshal(data, &remoteConfig); err != nil {
-			return pullResultMsg{success: false, error: "Error parsing remote config: " + err.Error()}
-		}
+	// Clone the repo using HTTPS with gh credential helper
+	repoURL := fmt.Sprintf("https://github.com/%s.git", repoName)
+	cloneCmd := exec.CommandContext(ctx, "git", "clone", repoURL, tmpDir)
+	cloneCmd.Stdin = nil
+	cloneCmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=credential.helper",
+		"GIT_CONFIG_VALUE_0=!gh auth git-credential",
+	)
+	output, err := cloneCmd.CombinedOutput()
+	if err != nil {
+		return pullResultMsg{success: false, error: "Error cloning repo: " + err.Error(), details: string(output)}
+	}
 
-		// Check for conflicts: if local has changes AND remote is newer
-		hasConflict := false
-		if localConfig.LastUpdate.After(remoteConfig.LastUpdate) {
-			// Local is newer - this is a conflict if remote also has changes
-			// For simplicity, we'll consider it a conflict if timestamps differ
-			hasConflict = !localConfig.LastUpdate.Equal(remoteConfig.LastUpdate)
-		}
+	// Read the remote config
+	remotePath := filepath.Join(tmpDir, ".todobi.conf")
+	data, err := os.ReadFile(remotePath)
+	if err != nil {
+		return pullResultMsg{success: false, noRemote: os.IsNotExist(err), error: "Error reading remote config: " + err.Error()}
+	}
 
-		return pullResultMsg{
-			success:      true,
-			remoteConfig: &remoteConfig,
-			hasConflict:  hasConflict,
-		}
+	var remoteConfig Config
+	data = relaxedJSON(data)
+	if err := json.Unmarshal(data, &remoteConfig); err != nil {
+		return pullResultMsg{success: false, error: "Error parsing remote config: " + err.Error()}
+	}
+	keepUnknownFields(&remoteConfig, data)
+	migrateConfig(&remoteConfig)
+
+	// Check for conflicts: if local has changes AND remote is newer
+	hasConflict := false
+	if localConfig.LastUpdate.After(remoteConfig.LastUpdate) {
+		// Local is newer - this is a conflict if remote also has changes
+		// For simplicity, we'll consider it a conflict if timestamps differ
+		hasConflict = !localConfig.LastUpdate.Equal(remoteConfig.LastUpdate)
+	}
+
+	return pullResultMsg{
+		success:      true,
+		remoteConfig: &remoteConfig,
+		hasConflict:  hasConflict,
 	}
 }
 
 // pullConfigFromGitHub is a helper for the --pull CLI flag
 func pullConfigFromGitHub() error {
-	repoName := "todobi-sync"
+	// An existing local config may point the sync at another owner
+	target := syncTarget{repoName: syncRepoName()}
+	if cfg, err := loadConfig(); err == nil {
+		target.owner = cfg.SyncOwner
+	}
 
 	// Check if gh CLI is installed
 	if err := exec.Command("gh", "--version").Run(); err != nil {
@@ -1341,12 +7366,12 @@ func pullConfigFromGitHub() error {
 	if err != nil {
 		return fmt.Errorf("error getting GitHub username: %w", err)
 	}
-	githubUser := strings.TrimSpace(string(usernameBytes))
+	repoName := target.repoPath(strings.TrimSpace(string(usernameBytes)))
 
 	// Check if repo exists
 	checkCmd := exec.Command("gh", "repo", "view", repoName, "--json", "name")
 	if checkCmd.Run() != nil {
-		return fmt.Errorf("remote repo 'todobi-sync' does not exist")
+		return fmt.Errorf("remote repo '%s' does not exist", repoName)
 	}
 
 	// Create temp directory
@@ -1358,7 +7383,7 @@ func pullConfigFromGitHub() error {
 	defer os.RemoveAll(tmpDir)
 
 	// Clone the repo using HTTPS with gh credential helper
-	repoURL := fmt.Sprintf("https://github.com/%s/%s.git", githubUser, repoName)
+	repoURL := fmt.Sprintf("https://github.com/%s.git", repoName)
 	cloneCmd := exec.Command("git", "clone", repoURL, tmpDir)
 	cloneCmd.Stdin = nil
 	cloneCmd.Env = append(os.Environ(),
@@ -1379,12 +7404,11 @@ func pullConfigFromGitHub() error {
 	}
 
 	// Write to local config path
-	home, err := os.UserHomeDir()
+	localPath, err := configPath()
 	if err != nil {
 		return fmt.Errorf("error getting home directory: %w", err)
 	}
 
-	localPath := filepath.Join(home, configFileName)
 	if err := os.WriteFile(localPath, data, 0644); err != nil {
 		return fmt.Errorf("error writing local config: %w", err)
 	}
@@ -1402,14 +7426,33 @@ func (m model) handleCategoryForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.editingCategory = nil
 		return m, nil
 
+	case "tab", "shift+tab":
+		// Cycle through the color palette
+		n := len(categoryPalette)
+		if msg.String() == "tab" {
+			m.categoryColorIndex = (m.categoryColorIndex + 1) % n
+		} else if m.categoryColorIndex < 0 {
+			m.categoryColorIndex = n - 1
+		} else {
+			m.categoryColorIndex = (m.categoryColorIndex + n - 1) % n
+		}
+		return m, nil
+
 	case "enter":
 		name := strings.TrimSpace(m.categoryInput.Value())
+		var color string
+		if m.categoryColorIndex >= 0 {
+			color = categoryPalette[m.categoryColorIndex]
+		} else if m.editingCategory != nil {
+			color = m.editingCategory.Color
+		}
 		if name != "" {
 			if m.editingCategory != nil {
 				// Edit existing category
 				for i := range m.config.Categories {
 					if m.config.Categories[i].ID == m.editingCategory.ID {
 						m.config.Categories[i].Name = name
+						m.config.Categories[i].Color = color
 						break
 					}
 				}
@@ -1419,11 +7462,13 @@ func (m model) handleCategoryForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				// Create new category
 				newCat := Category{
-					ID:   generateID(),
-					Name: name,
+					ID:    generateID(),
+					Name:  name,
+					Color: color,
 				}
 				m.config.Categories = append(m.config.Categories, newCat)
 				m.saveConfigAndMarkChanged()
+				m.updateCategoryList()
 				m.setStatus("Category created")
 			}
 		}
@@ -1440,7 +7485,30 @@ func (m model) handleCategoryForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m model) handleCategoryList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m.renamingCategory {
+		return m.handleCategoryRename(msg)
+	}
+
 	switch msg.String() {
+	case "r", "f2":
+		if item := m.categoryList.SelectedItem(); item != nil {
+			m.renamingCategory = true
+			m.categoryInput.SetValue(item.(Category).Name)
+			m.categoryInput.CursorEnd()
+			m.categoryInput.Focus()
+			m.showRenameInput()
+		}
+		return m, nil
+
+	case "C":
+		m.prevMode = categoryListView
+		m.mode = categoryFormView
+		m.editingCategory = nil
+		m.categoryColorIndex = 0
+		m.categoryInput.Focus()
+		m.categoryInput.SetValue("")
+		return m, textinput.Blink
+
 	case "e":
 		if item := m.categoryList.SelectedItem(); item != nil {
 			cat := item.(Category)
@@ -1448,6 +7516,13 @@ func (m model) handleCategoryList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.prevMode = categoryListView
 			m.mode = categoryFormView
 			m.categoryInput.SetValue(cat.Name)
+			// -1 keeps a hand-set color that isn't in the palette
+			m.categoryColorIndex = -1
+			for i, c := range categoryPalette {
+				if c == cat.Color {
+					m.categoryColorIndex = i
+				}
+			}
 			m.categoryInput.Focus()
 			return m, textinput.Blink
 		}
@@ -1458,26 +7533,121 @@ func (m model) handleCategoryList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			cat := item.(Category)
 			m.categoryToDelete = &cat
 			m.prevMode = categoryListView
+			if !m.config.confirmDeletes() {
+				return m.deleteCategory(m.config.categoryDelete())
+			}
 			m.mode = deleteConfirmView
 		}
 		return m, nil
 
+	case "n":
+		if item := m.categoryList.SelectedItem(); item != nil {
+			cat := item.(Category)
+			m.notesCategoryID = cat.ID
+			m.mode = categoryNotesView
+			m.notesTextarea.SetValue(cat.Notes)
+			m.originalNotes = cat.Notes
+			m.showingSaveConfirm = false
+			return m, m.notesTextarea.Focus()
+		}
+		return m, nil
+
+	case "M":
+		if item := m.categoryList.SelectedItem(); item != nil && len(m.config.Categories) > 1 {
+			cat := item.(Category)
+			m.mergeSource = &cat
+			m.mergeCursor = 0
+			m.mergeConfirm = false
+			m.mode = categoryMergeView
+		}
+		return m, nil
+
 	case "esc", "q":
 		m.mode = listView
 		return m, nil
 
 	default:
+		if m.config.WrapNavigation && wrapCursor(&m.categoryList, msg.String()) {
+			return m, nil
+		}
 		// Pass unhandled keys to the list for navigation
 		m.categoryList, cmd = m.categoryList.Update(msg)
 		return m, cmd
 	}
 }
 
+// handleCategoryRename edits the selected category's name in the list row
+func (m model) handleCategoryRename(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "enter":
+		name := strings.TrimSpace(m.categoryInput.Value())
+		if name == "" {
+			return m, nil
+		}
+		id := m.categoryList.SelectedItem().(Category).ID
+		for i := range m.config.Categories {
+			if m.config.Categories[i].ID == id {
+				m.config.Categories[i].Name = name
+				break
+			}
+		}
+		m.saveConfigAndMarkChanged()
+		m.updateLists()
+		m.setStatus("Category renamed")
+		fallthrough
+
+	case "esc":
+		m.renamingCategory = false
+		m.categoryInput.Blur()
+		m.categoryList.SetDelegate(list.NewDefaultDelegate())
+		m.updateCategoryList()
+		return m, nil
+	}
+
+	m.categoryInput, cmd = m.categoryInput.Update(msg)
+	m.showRenameInput()
+	return m, cmd
+}
+
+// showRenameInput redraws the selected category row with the current input
+func (m *model) showRenameInput() {
+	m.categoryList.SetDelegate(renameDelegate{
+		DefaultDelegate: list.NewDefaultDelegate(),
+		index:           m.categoryList.Index(),
+		input:           m.categoryInput.View(),
+	})
+}
+
+// defaultCategoryIndex is the category the new-task form starts on: the
+// open category tab, else the last one a task was added to (-1 for none)
+func (m model) defaultCategoryIndex() int {
+	for _, id := range []string{m.selectedCategoryID, m.lastCategoryID} {
+		for i, cat := range m.config.Categories {
+			if id != "" && cat.ID == id {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// defaultCategoryOffset skips ahead to the default category when focus
+// has just moved from the last input onto the category choices
+func (m model) defaultCategoryOffset() int {
+	if m.formFocus != len(m.taskInputs) {
+		return 0
+	}
+	return max(m.defaultCategoryIndex(), 0)
+}
+
 func (m model) handleTaskForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg.String() {
 	case "esc":
+		m.contentErr = ""
 		m.mode = m.prevMode
 		for i := range m.taskInputs {
 			m.taskInputs[i].Blur()
@@ -1485,9 +7655,13 @@ func (m model) handleTaskForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "up", "down":
+		if !m.requireContent() {
+			return m, textinput.Blink
+		}
 		// Navigate with arrow keys
 		if msg.String() == "down" {
 			m.formFocus++
+			m.formFocus += m.defaultCategoryOffset()
 		} else {
 			m.formFocus--
 		}
@@ -1509,6 +7683,9 @@ func (m model) handleTaskForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "enter":
+		if !m.requireContent() {
+			return m, textinput.Blink
+		}
 		// Progress through form or submit
 		catIndex := m.formFocus - len(m.taskInputs)
 
@@ -1533,12 +7710,22 @@ func (m model) handleTaskForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					Content:    content,
 					CategoryID: m.config.Categories[catIndex].ID,
 					Priority:   priority,
+					URLs:       parseURLs(m.taskInputs[2].Value()),
 					CreatedAt:  time.Now(),
 				}
+				newTask.logEvent("created", "", newTask.CreatedAt)
 				m.config.Tasks = append(m.config.Tasks, newTask)
+				m.lastCategoryID = newTask.CategoryID
+				if m.config.RememberCategory {
+					m.config.LastCategoryID = newTask.CategoryID
+				}
 				m.saveConfigAndMarkChanged()
 				m.updateLists()
-				m.setStatus("Task created")
+				if warning, over := m.config.overWIP(priority); over {
+					m.setStatus("Task created - " + warning)
+				} else {
+					m.setStatus("Task created")
+				}
 			}
 			m.mode = m.prevMode
 			for i := range m.taskInputs {
@@ -1549,6 +7736,7 @@ func (m model) handleTaskForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		// Otherwise, progress to next field
 		m.formFocus++
+		m.formFocus += m.defaultCategoryOffset()
 		if m.formFocus >= len(m.taskInputs)+len(m.config.Categories) {
 			m.formFocus = len(m.taskInputs) + len(m.config.Categories) - 1
 		}
@@ -1567,9 +7755,28 @@ func (m model) handleTaskForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.formFocus < len(m.taskInputs) {
 		m.taskInputs[m.formFocus], cmd = m.taskInputs[m.formFocus].Update(msg)
 	}
+	if strings.TrimSpace(m.taskInputs[0].Value()) != "" {
+		m.contentErr = ""
+	}
 	return m, cmd
 }
 
+// requireContent keeps the task and edit forms on the Content field, with
+// an inline error, until it holds more than whitespace
+func (m *model) requireContent() bool {
+	if strings.TrimSpace(m.taskInputs[0].Value()) != "" {
+		m.contentErr = ""
+		return true
+	}
+	m.contentErr = "A task needs some content"
+	m.formFocus = 0
+	for i := range m.taskInputs {
+		m.taskInputs[i].Blur()
+	}
+	m.taskInputs[0].Focus()
+	return false
+}
+
 func (m *model) setStatus(msg string) {
 	m.statusMsg = msg
 	m.statusUntil = time.Now().Add(2 * time.Second)
@@ -1579,6 +7786,9 @@ func (m model) View() string {
 	if !m.ready {
 		return "\nInitializing..."
 	}
+	if m.locked {
+		return m.renderLocked()
+	}
 
 	switch m.mode {
 	case firstRunView:
@@ -1597,6 +7807,10 @@ func (m model) View() string {
 		return m.renderTaskDetailView()
 	case completedView:
 		return m.renderCompletedView()
+	case snoozedView:
+		return m.renderSnoozedView()
+	case backlogView:
+		return m.renderBacklogView()
 	case deleteConfirmView:
 		return m.renderDeleteConfirm()
 	case categoryListView:
@@ -1605,12 +7819,38 @@ func (m model) View() string {
 		return m.renderSyncConfirm()
 	case pullConfirmView:
 		return m.renderPullConfirm()
+	case reloadConfirmView:
+		return m.renderReloadConfirm()
+	case createdDateView:
+		return m.renderCreatedDate()
+	case categoryNotesView:
+		if m.showingSaveConfirm {
+			return m.renderSaveConfirm()
+		}
+		return m.renderCategoryNotes()
+	case tourView:
+		return m.renderTour()
+	case snoozeDateView:
+		return m.renderSnoozeDate()
+	case dedupeConfirmView:
+		return m.renderDedupeConfirm()
+	case dashboardView:
+		return m.renderDashboard()
+	case categoryMergeView:
+		return m.renderCategoryMerge()
+	case completeCategoryView:
+		return m.renderCompleteCategory()
+	case syncErrorView:
+		return m.renderSyncError()
+	case resolveConflictsView:
+		return m.renderResolveConflicts()
 	default:
 		return m.renderListView()
 	}
 }
 
-func (m model) renderListView() string {
+// renderHeader draws the ASCII banner, separator bar, and category tabs
+func (m model) renderHeader() string {
 	var output strings.Builder
 
 	// Add ASCII art header with lighter teal background
@@ -1631,11 +7871,13 @@ func (m model) renderListView() string {
 		output.WriteString("\n")
 	}
 
-	// Add gray separator line
+	// Add gray separator line carrying the list title
 	grayBgStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("#333333")).
-		Width(m.width)
-	output.WriteString(grayBgStyle.Render(""))
+		Background(colorBar).
+		Foreground(colorSubtle).
+		Width(m.width).
+		Align(lipgloss.Center)
+	output.WriteString(grayBgStyle.Render(m.listTitle()))
 	output.WriteString("\n")
 
 	// Render category tabs at top (with 4 lines reserved)
@@ -1648,8 +7890,307 @@ func (m model) renderListView() string {
 		output.WriteString("\n")
 	}
 
+	return output.String()
+}
+
+func (m model) renderListView() string {
+	var output strings.Builder
+
+	output.WriteString(m.renderHeader())
+
 	// Render task list
-	output.WriteString(m.list.View())
+	if len(m.list.Items()) == 0 {
+		output.WriteString(emptyState(m.list, m.emptyListMessage()))
+	} else {
+		output.WriteString(m.list.View())
+	}
+	output.WriteString("\n")
+	output.WriteString(m.renderFooter())
+
+	return output.String()
+}
+
+// emptyState stands in for a list with no items, padded to the list's
+// height so the footer stays at the bottom
+func emptyState(l list.Model, message string) string {
+	style := lipgloss.NewStyle().Foreground(colorMuted).Padding(1, 2)
+	return lipgloss.NewStyle().Height(l.Height()).Render(style.Render(message))
+}
+
+// emptyListMessage explains why the active list is empty and what to do
+func (m model) emptyListMessage() string {
+	if m.fireDrill {
+		return "Nothing on fire 🎉 — press ! to go back"
+	}
+	if m.searchQuery != "" {
+		return fmt.Sprintf("No tasks match /%s — esc clears the search", m.searchQuery)
+	}
+	if f, ok := m.activeFilter(); ok {
+		return fmt.Sprintf("No tasks match %q — press F for the next smart list", f.Name)
+	}
+	if m.hiddenLowCount > 0 {
+		return fmt.Sprintf("Only low-priority tasks left (%d hidden) — press L to show them", m.hiddenLowCount)
+	}
+	if m.selectedCategoryID != "" {
+		return "No active tasks in " + m.config.categoryName(m.selectedCategoryID) + " — press T to add one"
+	}
+	return "No active tasks — press T to add one"
+}
+
+// taskGroup is a dashboard section: the pending tasks sharing a priority,
+// or a category when group_by is "category"
+type taskGroup struct {
+	Priority   Priority
+	ByCategory bool
+	Name       string
+	Color      string
+	Tasks      []TaskItem
+}
+
+// defaultDashboardPreview is how many tasks each dashboard group shows
+// unless dashboard_preview_count says otherwise
+const defaultDashboardPreview = 5
+
+// dashboardGroups splits the active list's tasks by priority, so the
+// dashboard always agrees with the list's category tab and filters
+func (m model) dashboardGroups() []taskGroup {
+	if m.config.groupBy() == "category" {
+		return m.categoryGroups()
+	}
+
+	byPriority := make(map[Priority][]TaskItem)
+	for _, item := range m.list.Items() {
+		t := item.(TaskItem)
+		byPriority[t.Priority] = append(byPriority[t.Priority], t)
+	}
+
+	var groups []taskGroup
+	for _, p := range []Priority{P0Critical, P1High, P2Medium, P3Low} {
+		tasks := byPriority[p]
+		if len(tasks) > 0 {
+			// Manual rank first; unranked tasks keep the list's order
+			sort.SliceStable(tasks, func(i, j int) bool {
+				return tasks[i].Rank < tasks[j].Rank
+			})
+			groups = append(groups, taskGroup{Priority: p, Tasks: tasks})
+		}
+	}
+	return groups
+}
+
+// categoryGroups splits the active list's tasks by category, in the
+// config's category order; tasks keep the list's sort within a section
+// and tasks whose category is missing end up in a trailing Unknown one
+func (m model) categoryGroups() []taskGroup {
+	byCategory := make(map[string][]TaskItem)
+	for _, item := range m.list.Items() {
+		t := item.(TaskItem)
+		byCategory[t.CategoryID] = append(byCategory[t.CategoryID], t)
+	}
+
+	var groups []taskGroup
+	for _, cat := range m.config.Categories {
+		if tasks := byCategory[cat.ID]; len(tasks) > 0 {
+			groups = append(groups, taskGroup{ByCategory: true, Name: cat.Name, Color: cat.Color, Tasks: tasks})
+			delete(byCategory, cat.ID)
+		}
+	}
+
+	var unknown []TaskItem
+	for _, item := range m.list.Items() {
+		t := item.(TaskItem)
+		if _, ok := byCategory[t.CategoryID]; ok {
+			unknown = append(unknown, t)
+		}
+	}
+	if len(unknown) > 0 {
+		groups = append(groups, taskGroup{ByCategory: true, Name: "Unknown", Tasks: unknown})
+	}
+	return groups
+}
+
+// moveInGroup swaps the dashboard's selected task with its neighbour in
+// the same priority group and renumbers the group's ranks to persist it.
+// It stops at the group's edges and at the preview cut-off.
+func (m model) moveInGroup(delta int) (tea.Model, tea.Cmd) {
+	index := m.dashCursor
+	for _, group := range m.dashboardGroups() {
+		visible := m.config.dashboardPreview(len(group.Tasks))
+		if index >= visible {
+			index -= visible
+			continue
+		}
+
+		if group.ByCategory {
+			// Ranks order tasks within a priority, which a category mixes
+			m.setStatus("Reordering works when the dashboard groups by priority")
+			return m, nil
+		}
+		target := index + delta
+		if target < 0 || target >= visible {
+			return m, nil
+		}
+		tasks := group.Tasks
+		tasks[index], tasks[target] = tasks[target], tasks[index]
+
+		rank := make(map[string]int)
+		for i, task := range tasks {
+			rank[task.ID] = i + 1
+		}
+		for i := range m.config.Tasks {
+			if r, ok := rank[m.config.Tasks[i].ID]; ok {
+				m.config.Tasks[i].Rank = r
+			}
+		}
+		m.saveConfigAndMarkChanged()
+		m.updateLists()
+		m.dashCursor += delta
+		return m, nil
+	}
+	return m, nil
+}
+
+// groupDone counts completed tasks in a dashboard group's priority (or
+// category), within the open category tab like the group itself
+func (m model) groupDone(group taskGroup) int {
+	done := 0
+	for _, task := range m.config.Tasks {
+		if !task.Done || (m.selectedCategoryID != "" && task.CategoryID != m.selectedCategoryID) {
+			continue
+		}
+		if group.ByCategory && m.config.categoryName(task.CategoryID) == group.Name ||
+			!group.ByCategory && task.Priority == group.Priority {
+			done++
+		}
+	}
+	return done
+}
+
+// dashboardTasks lists the tasks visible on the dashboard, top to bottom
+func (m model) dashboardTasks() []TaskItem {
+	var tasks []TaskItem
+	for _, group := range m.dashboardGroups() {
+		tasks = append(tasks, group.Tasks[:m.config.dashboardPreview(len(group.Tasks))]...)
+	}
+	return tasks
+}
+
+func (m model) handleDashboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	count := len(m.dashboardTasks())
+
+	switch msg.String() {
+	case "b", "esc":
+		m.mode = listView
+	case "up", "k":
+		if m.dashCursor > 0 {
+			m.dashCursor--
+		} else if m.config.WrapNavigation && count > 0 {
+			m.dashCursor = count - 1
+		}
+	case "down", "j":
+		if m.dashCursor < count-1 {
+			m.dashCursor++
+		} else if m.config.WrapNavigation {
+			m.dashCursor = 0
+		}
+	case "K", "shift+up":
+		return m.moveInGroup(-1)
+	case "J", "shift+down":
+		return m.moveInGroup(1)
+	case "enter", "i":
+		return m.viewTaskDetail()
+	case "x", " ":
+		updated, cmd := m.toggleTask()
+		dm := updated.(model)
+		dm.dashCursor = min(dm.dashCursor, max(len(dm.dashboardTasks())-1, 0))
+		return dm, cmd
+	case "tab":
+		updated, cmd := m.nextCategory()
+		dm := updated.(model)
+		dm.dashCursor = 0
+		return dm, cmd
+	case "shift+tab":
+		updated, cmd := m.prevCategory()
+		dm := updated.(model)
+		dm.dashCursor = 0
+		return dm, cmd
+	case "q", "ctrl+c":
+		return m.quit()
+	}
+	return m, nil
+}
+
+func (m model) renderDashboard() string {
+	var output strings.Builder
+
+	output.WriteString(m.renderHeader())
+
+	rowStyle := lipgloss.NewStyle().Foreground(colorText)
+	selectedStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(colorMuted)
+
+	var body strings.Builder
+	groups := m.dashboardGroups()
+
+	// Priority color legend
+	var legend []string
+	for _, p := range []Priority{P0Critical, P1High, P2Medium, P3Low} {
+		legend = append(legend, lipgloss.NewStyle().Foreground(lipgloss.Color(p.Color())).Render("● "+p.String()+" "+p.Label()))
+	}
+	body.WriteString("  " + strings.Join(legend, "  "))
+	body.WriteString("\n\n")
+
+	if len(groups) == 0 {
+		body.WriteString(dimStyle.Render("  Nothing pending here 🎉"))
+		body.WriteString("\n")
+	}
+
+	index := 0
+	for _, group := range groups {
+		counts := fmt.Sprintf("(%d pending, %d done)", len(group.Tasks), m.groupDone(group))
+		if group.ByCategory {
+			headerStyle := lipgloss.NewStyle().Foreground(categoryColor(group.Color)).Bold(true)
+			body.WriteString(headerStyle.Render(fmt.Sprintf("● %s %s", group.Name, counts)))
+		} else {
+			headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(group.Priority.Color())).Bold(true)
+			body.WriteString(headerStyle.Render(fmt.Sprintf("● %s %s %s", group.Priority, group.Priority.Label(), counts)))
+		}
+		if pending, limit := m.config.wip(group.Priority); limit > 0 && !group.ByCategory {
+			limitStyle := lipgloss.NewStyle().Foreground(colorMuted)
+			if pending > limit {
+				limitStyle = lipgloss.NewStyle().Foreground(colorWarning).Bold(true)
+			}
+			body.WriteString(limitStyle.Render(fmt.Sprintf("  WIP %d/%d", pending, limit)))
+		}
+		body.WriteString("\n")
+
+		preview := m.config.dashboardPreview(len(group.Tasks))
+		for i, task := range group.Tasks {
+			if i == preview {
+				body.WriteString(dimStyle.Render(fmt.Sprintf("    ... and %d more", len(group.Tasks)-preview)))
+				body.WriteString("\n")
+				break
+			}
+			line := ansi.Truncate(task.Content, max(m.width-24, 10), "…")
+			category := lipgloss.NewStyle().Foreground(categoryColor(task.CategoryColor)).Render("[" + task.CategoryName + "]")
+			if group.ByCategory {
+				// The header already names the category; tag the priority instead
+				category = lipgloss.NewStyle().Foreground(lipgloss.Color(task.Priority.Color())).Render("[" + task.Priority.String() + "]")
+			}
+			if index == m.dashCursor {
+				body.WriteString(selectedStyle.Render("  > "+task.state().checkbox()+" "+line) + " " + category)
+			} else {
+				body.WriteString(rowStyle.Render("    "+task.state().checkbox()+" "+line) + " " + category)
+			}
+			body.WriteString("\n")
+			index++
+		}
+		body.WriteString("\n")
+	}
+
+	// Fill the space the list would use so the footer stays at the bottom
+	bodyHeight := max(m.list.Height(), lipgloss.Height(body.String()))
+	output.WriteString(lipgloss.NewStyle().Height(bodyHeight).Render(body.String()))
 	output.WriteString("\n")
 	output.WriteString(m.renderFooter())
 
@@ -1659,64 +8200,92 @@ func (m model) renderListView() string {
 func (m model) renderCompletedView() string {
 	var output strings.Builder
 
-	// Add ASCII art header with lighter teal background
-	tealBgStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("#2d7a7a")).
-		Foreground(lipgloss.Color("#a0e0e0")).
-		Width(m.width).
-		Align(lipgloss.Center)
+	output.WriteString(m.renderHeader())
 
-	asciiArt := []string{
-		"░▀█▀░█▀█░█▀▄░█▀█░█▀▄░▀█▀",
-		"░░█░░█░█░█░█░█░█░█▀▄░░█░",
-		"░░▀░░▀▀▀░▀▀░░▀▀▀░▀▀░░▀▀▀",
+	// Render completed list
+	if len(m.completedList.Items()) == 0 {
+		message := "No completed tasks yet"
+		if m.selectedCategoryID != "" {
+			message += " in " + m.config.categoryName(m.selectedCategoryID)
+		}
+		output.WriteString(emptyState(m.completedList, message))
+	} else {
+		output.WriteString(m.completedList.View())
 	}
+	output.WriteString("\n")
+	output.WriteString(m.renderFooter())
 
-	for _, line := range asciiArt {
-		output.WriteString(tealBgStyle.Render(line))
-		output.WriteString("\n")
-	}
+	return output.String()
+}
 
-	// Add gray separator line
-	grayBgStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("#333333")).
-		Width(m.width)
-	output.WriteString(grayBgStyle.Render(""))
-	output.WriteString("\n")
+func (m model) renderSnoozedView() string {
+	var output strings.Builder
 
-	// Render category tabs at top (with 4 lines reserved)
-	tabs := m.renderTabs()
-	tabLines := strings.Split(tabs, "\n")
-	output.WriteString(tabs)
+	output.WriteString(m.renderHeader())
 
-	// Pad to ensure 4 lines total for tabs area
-	for i := len(tabLines); i < 4; i++ {
-		output.WriteString("\n")
+	// Render snoozed list
+	if len(m.snoozedList.Items()) == 0 {
+		output.WriteString(emptyState(m.snoozedList, "Nothing snoozed — press z on a task to hide it until tomorrow"))
+	} else {
+		output.WriteString(m.snoozedList.View())
 	}
+	output.WriteString("\n")
+	output.WriteString(m.renderFooter())
 
-	// Render completed list
-	output.WriteString(m.completedList.View())
+	return output.String()
+}
+
+func (m model) renderBacklogView() string {
+	var output strings.Builder
+
+	output.WriteString(m.renderHeader())
+
+	if len(m.backlogList.Items()) == 0 {
+		output.WriteString(emptyState(m.backlogList, "Backlog is empty — press M on a task to park it here for someday"))
+	} else {
+		output.WriteString(m.backlogList.View())
+	}
 	output.WriteString("\n")
 	output.WriteString(m.renderFooter())
 
 	return output.String()
 }
 
+// renderLocked replaces the whole screen while the idle lock is on
+func (m model) renderLocked() string {
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render("🔒 todobi"),
+		"",
+		lipgloss.NewStyle().Foreground(colorMuted).Render("Locked after inactivity - press any key"),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
 func (m model) renderCategoryList() string {
 	var output strings.Builder
 
-	output.WriteString(m.categoryList.View())
+	if len(m.categoryList.Items()) == 0 {
+		output.WriteString(emptyState(m.categoryList, "No categories — press C to create one"))
+	} else {
+		output.WriteString(m.categoryList.View())
+	}
 	output.WriteString("\n")
 
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
-	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#4ec9b0"))
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	statusStyle := lipgloss.NewStyle().Foreground(colorAccent)
 
 	status := ""
 	if time.Now().Before(m.statusUntil) {
 		status = statusStyle.Render(m.statusMsg) + " "
 	}
 
-	output.WriteString(status + helpStyle.Render("e: edit | d: delete | esc: back"))
+	help := "C: new | r: rename | e: edit | n: notes | d: delete | M: merge into... | esc: back"
+	if m.renamingCategory {
+		help = "enter: save name | esc: cancel"
+	} else if m.readonly {
+		help = "esc: back"
+	}
+	output.WriteString(status + helpStyle.Render(help))
 
 	return output.String()
 }
@@ -1726,7 +8295,7 @@ func (m model) renderCategoryForm() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#4ec9b0"))
+		Foreground(colorAccent)
 
 	if m.editingCategory != nil {
 		output.WriteString(titleStyle.Render("Edit Category"))
@@ -1739,8 +8308,23 @@ func (m model) renderCategoryForm() string {
 	output.WriteString(m.categoryInput.View())
 	output.WriteString("\n\n")
 
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
-	output.WriteString(helpStyle.Render("enter: save | esc: cancel"))
+	output.WriteString("Color:\n")
+	if m.categoryColorIndex < 0 && m.editingCategory != nil {
+		swatch := lipgloss.NewStyle().Foreground(categoryColor(m.editingCategory.Color)).Render("●")
+		output.WriteString("[" + swatch + "]")
+	}
+	for i, c := range categoryPalette {
+		swatch := lipgloss.NewStyle().Foreground(categoryColor(c)).Render("●")
+		if i == m.categoryColorIndex {
+			output.WriteString("[" + swatch + "]")
+		} else {
+			output.WriteString(" " + swatch + " ")
+		}
+	}
+	output.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("tab: color | enter: save | esc: cancel"))
 
 	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
 }
@@ -1750,53 +8334,121 @@ func (m model) renderTaskForm() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#4ec9b0"))
+		Foreground(colorAccent)
 
 	output.WriteString(titleStyle.Render("New Task"))
 	output.WriteString("\n\n")
 
 	// Task content input
-	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#999"))
+	labelStyle := lipgloss.NewStyle().Foreground(colorSubtle)
 	if m.formFocus == 0 {
-		labelStyle = labelStyle.Foreground(lipgloss.Color("#4ec9b0"))
+		labelStyle = labelStyle.Foreground(colorAccent)
 	}
 	output.WriteString(labelStyle.Render("Content:"))
 	output.WriteString("\n")
 	output.WriteString(m.taskInputs[0].View())
-	output.WriteString("\n\n")
+	output.WriteString("\n")
+	if m.contentErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#d73a4a"))
+		output.WriteString(errStyle.Render(m.contentErr))
+	}
+	output.WriteString("\n")
 
 	// Priority input
-	labelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#999"))
+	labelStyle = lipgloss.NewStyle().Foreground(colorSubtle)
 	if m.formFocus == 1 {
-		labelStyle = labelStyle.Foreground(lipgloss.Color("#4ec9b0"))
+		labelStyle = labelStyle.Foreground(colorAccent)
 	}
 	output.WriteString(labelStyle.Render("Priority (0-3):"))
 	output.WriteString("\n")
 	output.WriteString(m.taskInputs[1].View())
 	output.WriteString("\n\n")
 
+	// URL input
+	labelStyle = lipgloss.NewStyle().Foreground(colorSubtle)
+	if m.formFocus == 2 {
+		labelStyle = labelStyle.Foreground(colorAccent)
+	}
+	output.WriteString(labelStyle.Render("URLs (separate with spaces):"))
+	output.WriteString("\n")
+	output.WriteString(m.taskInputs[2].View())
+	output.WriteString("\n\n")
+
 	// Category selection
-	output.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#999")).Render("Category:"))
+	output.WriteString(lipgloss.NewStyle().Foreground(colorSubtle).Render("Category:"))
 	output.WriteString("\n")
 
-	for i, cat := range m.config.Categories {
+	defaultID := ""
+	if i := m.defaultCategoryIndex(); i >= 0 {
+		defaultID = m.config.Categories[i].ID
+	}
+	output.WriteString(m.renderCategoryPicker(defaultID))
+
+	output.WriteString("\n")
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("arrows: navigate | enter: next/save | esc: cancel"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+// categoryWindow returns the slice of categories that fits on screen in the
+// task forms, scrolled so the focused category stays visible
+func (m model) categoryWindow() (start, end int) {
+	total := len(m.config.Categories)
+	// Title, inputs, labels, help, and padding take roughly 18 lines, plus
+	// the edit form's kept-fields summary
+	reserved := 18
+	if kept := m.keptTaskFields(); len(kept) > 0 {
+		reserved += len(kept) + 2
+	}
+	visible := max(m.height-reserved, 3)
+	if total <= visible {
+		return 0, total
+	}
+
+	focused := m.formFocus - len(m.taskInputs)
+	if focused > 0 {
+		start = focused - visible/2
+	}
+	start = max(0, min(start, total-visible))
+	return start, start + visible
+}
+
+// renderCategoryPicker draws the scrollable category choices for the task
+// forms; currentID marks the task's existing category with "*"
+func (m model) renderCategoryPicker(currentID string) string {
+	var output strings.Builder
+	moreStyle := lipgloss.NewStyle().Foreground(colorMuted).Italic(true)
+
+	start, end := m.categoryWindow()
+	if start > 0 {
+		output.WriteString(moreStyle.Render(fmt.Sprintf("  ↑ %d more", start)) + "\n")
+	}
+
+	for i := start; i < end; i++ {
+		cat := m.config.Categories[i]
 		catIndex := len(m.taskInputs) + i
 		cursor := "  "
-		style := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
+		style := lipgloss.NewStyle().Foreground(colorMuted)
+
+		// Highlight current category
+		if cat.ID == currentID && m.formFocus != catIndex {
+			cursor = "* "
+		}
 
 		if m.formFocus == catIndex {
 			cursor = "> "
-			style = style.Foreground(lipgloss.Color("#4ec9b0")).Bold(true)
+			style = style.Foreground(colorAccent).Bold(true)
 		}
 
 		output.WriteString(cursor + style.Render(cat.Name) + "\n")
 	}
 
-	output.WriteString("\n")
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
-	output.WriteString(helpStyle.Render("arrows: navigate | enter: next/save | esc: cancel"))
+	if end < len(m.config.Categories) {
+		output.WriteString(moreStyle.Render(fmt.Sprintf("  ↓ %d more", len(m.config.Categories)-end)) + "\n")
+	}
 
-	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+	return output.String()
 }
 
 func (m model) renderDeleteConfirm() string {
@@ -1811,7 +8463,7 @@ func (m model) renderDeleteConfirm() string {
 		output.WriteString("\n\n")
 
 		taskStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#d4d4d4"))
+			Foreground(colorText)
 		output.WriteString(taskStyle.Render(m.taskToDelete.Content))
 		output.WriteString("\n\n")
 	} else if m.categoryToDelete != nil {
@@ -1819,12 +8471,30 @@ func (m model) renderDeleteConfirm() string {
 		output.WriteString("\n\n")
 
 		catStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#d4d4d4"))
+			Foreground(colorText)
 		output.WriteString(catStyle.Render(m.categoryToDelete.Name))
 		output.WriteString("\n\n")
+
+		if count := m.config.tasksIn(m.categoryToDelete.ID); count > 0 {
+			output.WriteString(catStyle.Render(fmt.Sprintf("It still has %d tasks. What should happen to them?", count)))
+			output.WriteString("\n\n")
+
+			optionStyle := lipgloss.NewStyle().Foreground(colorAccent)
+			labels := map[string]string{
+				"reassign": "Move them to " + uncategorizedName,
+				"delete":   "Delete them too",
+			}
+			if how := m.config.categoryDelete(); how != "refuse" {
+				output.WriteString(optionStyle.Render("Y: ") + labels[how] + " (default)\n")
+			}
+			output.WriteString(optionStyle.Render("R: ") + labels["reassign"] + "\n")
+			output.WriteString(optionStyle.Render("D: ") + labels["delete"] + "\n")
+			output.WriteString(optionStyle.Render("N/Esc: ") + "Keep the category\n")
+			return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+		}
 	}
 
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
 	output.WriteString(helpStyle.Render("y: delete | n/esc: cancel"))
 
 	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
@@ -1835,23 +8505,23 @@ func (m model) renderSyncConfirm() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#4ec9b0"))
+		Foreground(colorAccent)
 
 	output.WriteString(titleStyle.Render("Sync to GitHub?"))
 	output.WriteString("\n\n")
 
 	infoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#d4d4d4"))
+		Foreground(colorText)
 
-	output.WriteString(infoStyle.Render("This will sync your .todobi.conf to a private GitHub repo"))
+	output.WriteString(infoStyle.Render("This will sync your .todobi.conf to the GitHub repo"))
 	output.WriteString("\n")
-	output.WriteString(infoStyle.Render("named 'todobi-sync'."))
+	output.WriteString(infoStyle.Render(fmt.Sprintf("'%s' (%s).", m.config.syncRepoPath(), m.config.syncVisibility())))
 	output.WriteString("\n\n")
 
 	if m.syncInProgress {
 		output.WriteString(fmt.Sprintf("%s %s", m.spinner.View(), infoStyle.Render("Syncing to GitHub...")))
 	} else {
-		helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
+		helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
 		output.WriteString(helpStyle.Render("y: sync | n/esc: cancel"))
 	}
 
@@ -1863,13 +8533,13 @@ func (m model) renderPullConfirm() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#4ec9b0"))
+		Foreground(colorAccent)
 
 	infoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#d4d4d4"))
+		Foreground(colorText)
 
 	warningStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ffc107")).
+		Foreground(colorWarning).
 		Bold(true)
 
 	if m.pullInProgress {
@@ -1885,7 +8555,7 @@ func (m model) renderPullConfirm() string {
 		output.WriteString(infoStyle.Render("Choose how to resolve:"))
 		output.WriteString("\n\n")
 
-		optionStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#4ec9b0"))
+		optionStyle := lipgloss.NewStyle().Foreground(colorAccent)
 		output.WriteString(optionStyle.Render("L: "))
 		output.WriteString(infoStyle.Render("Keep Local (discard remote changes)"))
 		output.WriteString("\n")
@@ -1894,11 +8564,283 @@ func (m model) renderPullConfirm() string {
 		output.WriteString("\n")
 		output.WriteString(optionStyle.Render("M: "))
 		output.WriteString(infoStyle.Render("Merge (combine both, newer tasks win)"))
+		output.WriteString("\n")
+		output.WriteString(optionStyle.Render("S: "))
+		output.WriteString(infoStyle.Render("Selective merge (pick categories from remote)"))
+		if changed := len(diffConfigs(m.config, m.remoteConfig).changed); changed > 0 {
+			output.WriteString("\n")
+			output.WriteString(optionStyle.Render("C: "))
+			output.WriteString(infoStyle.Render(fmt.Sprintf("Resolve conflicts (pick a side for each of %d changed tasks)", changed)))
+		}
+		output.WriteString("\n\n")
+
+		if m.pullSelecting {
+			output.WriteString(infoStyle.Render("Merge these remote categories:"))
+			output.WriteString("\n")
+			for i, cat := range m.remoteConfig.Categories {
+				cursor := "  "
+				if i == m.pullCursor {
+					cursor = optionStyle.Render("> ")
+				}
+				check := "[ ]"
+				if m.pullSelected[cat.ID] {
+					check = "[x]"
+				}
+				output.WriteString(fmt.Sprintf("%s%s %s\n", cursor, check, infoStyle.Render(cat.Name)))
+			}
+			output.WriteString("\n")
+		}
+
+		helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+		if m.pullSelecting {
+			output.WriteString(helpStyle.Render("space: toggle | enter: merge selected | esc: back"))
+		} else {
+			output.WriteString(helpStyle.Render("esc: cancel"))
+		}
+	}
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+func (m model) renderResolveConflicts() string {
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorWarning)
+
+	id := m.conflictIDs[m.conflictIndex]
+	var local, remote Task
+	for _, task := range m.config.Tasks {
+		if task.ID == id {
+			local = task
+		}
+	}
+	for _, task := range m.remoteConfig.Tasks {
+		if task.ID == id {
+			remote = task
+		}
+	}
+
+	output.WriteString(titleStyle.Render(fmt.Sprintf("Conflict %d of %d", m.conflictIndex+1, len(m.conflictIDs))))
+	output.WriteString("\n\n")
+
+	// One row per field; values that differ are highlighted on both sides
+	fields := func(t Task, cfg *Config) []string {
+		return []string{t.Content, cfg.categoryName(t.CategoryID), t.Priority.String(), string(t.state()), t.Notes}
+	}
+	labels := []string{"Content", "Category", "Priority", "Status", "Notes"}
+	localFields, remoteFields := fields(local, m.config), fields(remote, m.remoteConfig)
+
+	colWidth := max((m.width-20)/2, 20)
+	labelStyle := lipgloss.NewStyle().Foreground(colorMuted).Width(10)
+	sameStyle := lipgloss.NewStyle().Foreground(colorText).Width(colWidth).MaxHeight(4)
+	diffStyle := sameStyle.Foreground(colorWarning).Bold(true)
+	headStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Width(colWidth)
+
+	output.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		labelStyle.Render(""), headStyle.Render("Local"), " ", headStyle.Render("Remote")))
+	output.WriteString("\n")
+	for i, label := range labels {
+		style := sameStyle
+		if localFields[i] != remoteFields[i] {
+			style = diffStyle
+		}
+		output.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+			labelStyle.Render(label), style.Render(localFields[i]), " ", style.Render(remoteFields[i])))
+		output.WriteString("\n")
+	}
+	output.WriteString("\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("l: keep local | r: take remote | ←: previous | esc: back to strategies"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+func (m model) renderReloadConfirm() string {
+	var output strings.Builder
+
+	warningStyle := lipgloss.NewStyle().
+		Foreground(colorWarning).
+		Bold(true)
+
+	infoStyle := lipgloss.NewStyle().
+		Foreground(colorText)
+
+	output.WriteString(warningStyle.Render("Reload from disk?"))
+	output.WriteString("\n\n")
+	output.WriteString(infoStyle.Render("The config file changed on disk and you have unsynced changes."))
+	output.WriteString("\n")
+	output.WriteString(infoStyle.Render("Reloading replaces the tasks in memory with the file's contents."))
+	output.WriteString("\n\n")
+
+	if m.pendingReload != nil {
+		diff := diffConfigs(m.config, m.pendingReload)
+		output.WriteString(infoStyle.Render(fmt.Sprintf("Only in memory: %d tasks", len(diff.onlyLocal))))
+		output.WriteString("\n")
+		output.WriteString(infoStyle.Render(fmt.Sprintf("Only on disk:   %d tasks", len(diff.onlyRemote))))
+		output.WriteString("\n")
+		output.WriteString(infoStyle.Render(fmt.Sprintf("Changed:        %d tasks", len(diff.changed))))
+		output.WriteString("\n\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("y: reload | n/esc: keep memory"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+func (m model) renderSnoozeDate() string {
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent)
+
+	output.WriteString(titleStyle.Render("Snooze Until"))
+	output.WriteString("\n\n")
+
+	// The task being snoozed is selected in the list we came from
+	m.mode = m.prevMode
+	if task, ok := m.selectedTask(); ok {
+		output.WriteString(task.Content)
+		output.WriteString("\n\n")
+	}
+
+	output.WriteString(m.dateInput.View())
+	output.WriteString("\n")
+
+	if m.dateErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#d73a4a"))
+		output.WriteString(errStyle.Render(m.dateErr))
+	}
+	output.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("2006-01-02, 01/02/2006, tomorrow, +3d, next monday, PT2H, P3D | enter: snooze | esc: cancel"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+func (m model) renderCreatedDate() string {
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent)
+
+	output.WriteString(titleStyle.Render("Created Date"))
+	output.WriteString("\n\n")
+
+	if m.editingTask != nil {
+		output.WriteString(m.editingTask.Content)
 		output.WriteString("\n\n")
+	}
+
+	output.WriteString(m.dateInput.View())
+	output.WriteString("\n")
+
+	if m.dateErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#d73a4a"))
+		output.WriteString(errStyle.Render(m.dateErr))
+	}
+	output.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("2006-01-02, 01/02/2006, yesterday, 3d, 2w | enter: set | esc: cancel"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+func (m model) renderDedupeConfirm() string {
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#d73a4a"))
+
+	keepStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#4caf50"))
+	removeStyle := lipgloss.NewStyle().Foreground(colorMuted).Strikethrough(true)
+
+	count := 0
+	for _, group := range m.duplicates {
+		count += len(group) - 1
+	}
+
+	output.WriteString(titleStyle.Render(fmt.Sprintf("Remove %d duplicate tasks?", count)))
+	output.WriteString("\n\n")
+
+	for _, group := range m.duplicates {
+		output.WriteString(keepStyle.Render("keep   " + group[0].Content))
+		output.WriteString("\n")
+		for _, task := range group[1:] {
+			output.WriteString(removeStyle.Render("remove " + task.Content))
+			output.WriteString("\n")
+		}
+		output.WriteString("\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("y: remove duplicates | n/esc: cancel"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+func (m model) renderCompleteCategory() string {
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorWarning)
+
+	pending := m.config.pendingIn(m.selectedCategoryID)
+	output.WriteString(titleStyle.Render(fmt.Sprintf("Complete all %d pending tasks in %s?",
+		pending, m.config.categoryName(m.selectedCategoryID))))
+	output.WriteString("\n\n")
+
+	taskStyle := lipgloss.NewStyle().Foreground(colorText)
+	for _, task := range m.config.Tasks {
+		if task.CategoryID == m.selectedCategoryID && !task.Done && !task.Backlog {
+			output.WriteString(taskStyle.Render("  " + task.Content))
+			output.WriteString("\n")
+		}
+	}
+	output.WriteString("\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("y: complete all | n/esc: cancel"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
+func (m model) renderSyncError() string {
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#d73a4a"))
 
-		helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
-		output.WriteString(helpStyle.Render("esc: cancel"))
+	output.WriteString(titleStyle.Render("Last sync error"))
+	output.WriteString("\n\n")
+	output.WriteString(lipgloss.NewStyle().Foreground(colorText).Render(m.syncError))
+	output.WriteString("\n\n")
+
+	details := m.syncDetails
+	if details == "" {
+		details = "(no command output captured)"
 	}
+	detailStyle := lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorFaint).
+		Padding(0, 1).
+		Width(max(m.width-8, 20))
+	output.WriteString(detailStyle.Render(details))
+	output.WriteString("\n\n")
+
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	output.WriteString(helpStyle.Render("E/esc: close"))
 
 	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
 }
@@ -1906,13 +8848,13 @@ func (m model) renderPullConfirm() string {
 func (m model) renderSaveConfirm() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#ffc107"))
+		Foreground(colorWarning)
 
 	infoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#d4d4d4"))
+		Foreground(colorText)
 
 	optionStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#4ec9b0"))
+		Foreground(colorAccent)
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		titleStyle.Render("Unsaved Changes"),
@@ -1927,7 +8869,7 @@ func (m model) renderSaveConfirm() string {
 
 	dialog := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#ffc107")).
+		BorderForeground(colorWarning).
 		Padding(1, 2).
 		Render(content)
 
@@ -1935,16 +8877,41 @@ func (m model) renderSaveConfirm() string {
 }
 
 func (m model) renderFooter() string {
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
-	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#4ec9b0"))
-	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ffc107")).Bold(true)
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
+	statusStyle := lipgloss.NewStyle().Foreground(colorAccent)
+	warningStyle := lipgloss.NewStyle().Foreground(colorWarning).Bold(true)
 
 	status := ""
-	if time.Now().Before(m.statusUntil) {
+	if m.busy != "" {
+		status = statusStyle.Render(m.spinner.View()+" "+m.busy+"...") + " "
+	} else if time.Now().Before(m.statusUntil) {
 		status = statusStyle.Render(m.statusMsg) + " "
 	} else if m.configChanged {
 		status = warningStyle.Render("Unsynced changes - Press G to sync ") + " "
 	}
+	prefix := m.renderProgress() + " " + m.renderFocusTimer()
+	status = prefix + status
+
+	// Visual mode shows its operators, or the one waiting for input
+	if m.visual {
+		count := len(m.visualTasks())
+		switch m.visualOp {
+		case "d":
+			return status + warningStyle.Render(fmt.Sprintf("Delete %s? y/n", taskCount(count)))
+		case "m":
+			var choices []string
+			for i, cat := range m.config.Categories[:min(len(m.config.Categories), 9)] {
+				choices = append(choices, fmt.Sprintf("%d: %s", i+1, cat.Name))
+			}
+			return status + helpStyle.Render(wrapText(fmt.Sprintf("Move %s to %s | esc: cancel", taskCount(count), strings.Join(choices, "  ")), m.width-lipgloss.Width(status)))
+		}
+		return status + helpStyle.Render(wrapText(fmt.Sprintf("VISUAL %d selected | j/k: extend | d: delete | x: complete | m: move | esc: cancel", count), m.width-lipgloss.Width(status)))
+	}
+
+	// The search input takes the footer's place while it has focus
+	if m.searching {
+		return status + m.searchInput.View() + helpStyle.Render("  ↑/↓: history | enter: keep | esc: clear")
+	}
 
 	var helpText string
 	if m.mode == completedView {
@@ -1956,12 +8923,33 @@ func (m model) renderFooter() string {
 		}
 		countInfo := fmt.Sprintf("Showing all %d completed tasks | ", completedCount)
 		helpText = countInfo + "v: back | i: details | x: reopen | d: delete | q: quit"
+	} else if m.mode == snoozedView {
+		helpText = "S: back | z: wake | i: details | d: delete | q: quit"
+	} else if m.mode == backlogView {
+		helpText = "B: back | M: make active | tab: categories | i: details | x: done | d: delete | q: quit"
+	} else if m.mode == dashboardView {
+		helpText = "b/esc: list | ↑/↓: move | J/K: reorder | enter: details | x: done | tab: categories | q: quit"
+	} else if m.fireDrill {
+		helpText = "!: leave fire drill | x: done | enter: details | q: quit"
+	} else if m.list.Help.ShowAll {
+		helpText = "?: fewer keys"
 	} else {
-		helpText = "tab/shift+tab: categories | c: manage | C: new | T: task | v: completed | x: done | q: quit"
+		helpText = "tab/shift+tab: categories | ?: all keys"
+	}
+	if m.readonly {
+		helpText = "READ-ONLY | tab/shift+tab: categories | v: completed | S: snoozed | b: dashboard | enter: details | ?: all keys | q: quit"
+		if !readonlyFlag {
+			helpText += " | P: leave read-only"
+		}
 	}
 
 	// Wrap help text to terminal width
 	availableWidth := m.width - lipgloss.Width(status)
+	if availableWidth < 40 {
+		// Drop the message first, keeping progress and the focus timer
+		status = prefix
+		availableWidth = m.width - lipgloss.Width(status)
+	}
 	if availableWidth < 40 {
 		availableWidth = m.width
 		status = ""
@@ -1971,6 +8959,94 @@ func (m model) renderFooter() string {
 	return status + helpStyle.Render(wrappedHelp)
 }
 
+// progress counts completed tasks against all tasks, leaving out the
+// backlog, and waiting tasks when ExcludeWaiting is set
+func (c *Config) progress() (done, total int) {
+	for _, task := range c.Tasks {
+		if task.inBacklog() || c.ExcludeWaiting && task.state() == StateWaiting {
+			continue
+		}
+		total++
+		if task.Done {
+			done++
+		}
+	}
+	return done, total
+}
+
+// weightedProgress is progress with each task counting its priority's
+// weight; priorities missing from weights count 1, as in progress
+func (c *Config) weightedProgress(weights map[Priority]int) (done, total int) {
+	for _, task := range c.Tasks {
+		if task.inBacklog() || c.ExcludeWaiting && task.state() == StateWaiting {
+			continue
+		}
+		weight, ok := weights[task.Priority]
+		if !ok {
+			weight = 1
+		}
+		total += weight
+		if task.Done {
+			done += weight
+		}
+	}
+	return done, total
+}
+
+// priorityWeights converts PriorityWeights to Priority keys, or nil when
+// no weights are configured
+func (c *Config) priorityWeights() map[Priority]int {
+	if len(c.PriorityWeights) == 0 {
+		return nil
+	}
+	weights := make(map[Priority]int)
+	for _, p := range []Priority{P0Critical, P1High, P2Medium, P3Low} {
+		if w, ok := c.PriorityWeights[p.String()]; ok && w >= 0 {
+			weights[p] = w
+		}
+	}
+	return weights
+}
+
+// renderProgress draws a compact completion bar like "[████░░░░░░] 40%".
+// With priority weights configured the bar follows the weighted figure
+// and the label shows both.
+func (m model) renderProgress() string {
+	const barWidth = 10
+
+	done, total := m.config.progress()
+	percent := 0
+	filled := 0
+	if total > 0 {
+		percent = done * 100 / total
+		filled = done * barWidth / total
+	}
+	label := fmt.Sprintf("%d%% done", percent)
+
+	if weights := m.config.priorityWeights(); weights != nil {
+		done, total := m.config.weightedProgress(weights)
+		weighted := 0
+		filled = 0
+		if total > 0 {
+			weighted = done * 100 / total
+			filled = done * barWidth / total
+		}
+		label = fmt.Sprintf("%d%% done · %d%% weighted", percent, weighted)
+	}
+
+	emptyStyle := lipgloss.NewStyle().Foreground(colorFaint)
+	labelStyle := lipgloss.NewStyle().Foreground(colorSubtle)
+
+	var bar strings.Builder
+	bar.WriteString("[")
+	for _, color := range m.config.progressFill(barWidth)[:filled] {
+		bar.WriteString(lipgloss.NewStyle().Foreground(color).Render("█"))
+	}
+	bar.WriteString(emptyStyle.Render(strings.Repeat("░", barWidth-filled)) + "] ")
+	bar.WriteString(labelStyle.Render(label))
+	return bar.String()
+}
+
 func wrapText(text string, width int) string {
 	if width <= 0 {
 		return text
@@ -2011,20 +9087,7 @@ func wrapText(text string, width int) string {
 }
 
 func (m model) startEditTask() (tea.Model, tea.Cmd) {
-	var selectedTask Task
-	found := false
-
-	if m.mode == completedView {
-		if item := m.completedList.SelectedItem(); item != nil {
-			selectedTask = item.(TaskItem).Task
-			found = true
-		}
-	} else {
-		if item := m.list.SelectedItem(); item != nil {
-			selectedTask = item.(TaskItem).Task
-			found = true
-		}
-	}
+	selectedTask, found := m.selectedTask()
 
 	if !found {
 		return m, nil
@@ -2047,26 +9110,15 @@ func (m model) startEditTask() (tea.Model, tea.Cmd) {
 		m.taskInputs[0].Focus()
 		m.taskInputs[1].SetValue(fmt.Sprintf("%d", m.editingTask.Priority))
 		m.taskInputs[1].Blur()
+		m.taskInputs[2].SetValue(strings.Join(m.editingTask.URLs, " "))
+		m.taskInputs[2].Blur()
 	}
 
 	return m, textinput.Blink
 }
 
 func (m model) viewTaskDetail() (tea.Model, tea.Cmd) {
-	var selectedTask Task
-	found := false
-
-	if m.mode == completedView {
-		if item := m.completedList.SelectedItem(); item != nil {
-			selectedTask = item.(TaskItem).Task
-			found = true
-		}
-	} else {
-		if item := m.list.SelectedItem(); item != nil {
-			selectedTask = item.(TaskItem).Task
-			found = true
-		}
-	}
+	selectedTask, found := m.selectedTask()
 
 	if !found {
 		return m, nil
@@ -2086,8 +9138,10 @@ func (m model) viewTaskDetail() (tea.Model, tea.Cmd) {
 	if m.editingTask != nil {
 		m.notesTextarea.SetValue(m.editingTask.Notes)
 		m.originalNotes = m.editingTask.Notes // Track original for change detection
+		m.originalPriority = m.editingTask.Priority
 	}
 	m.showingSaveConfirm = false // Reset confirmation state
+	m.notesAutosaved = false
 	m.notesTextarea.Focus()
 
 	return m, textarea.Blink
@@ -2098,6 +9152,7 @@ func (m model) handleTaskEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch msg.String() {
 	case "esc":
+		m.contentErr = ""
 		m.mode = m.prevMode
 		m.editingTask = nil
 		for i := range m.taskInputs {
@@ -2106,6 +9161,9 @@ func (m model) handleTaskEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "up", "down":
+		if !m.requireContent() {
+			return m, textinput.Blink
+		}
 		// Navigate with arrow keys
 		if msg.String() == "down" {
 			m.formFocus++
@@ -2130,6 +9188,9 @@ func (m model) handleTaskEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "enter":
+		if !m.requireContent() {
+			return m, textinput.Blink
+		}
 		// Progress through form or submit
 		catIndex := m.formFocus - len(m.taskInputs)
 
@@ -2152,9 +9213,21 @@ func (m model) handleTaskEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// Find and update the task in config
 				for i := range m.config.Tasks {
 					if m.config.Tasks[i].ID == m.editingTask.ID {
-						m.config.Tasks[i].Content = content
-						m.config.Tasks[i].Priority = priority
-						m.config.Tasks[i].CategoryID = m.config.Categories[catIndex].ID
+						task := &m.config.Tasks[i]
+						now := time.Now()
+						if task.Content != content {
+							task.logEvent("edited", content, now)
+						}
+						if task.Priority != priority {
+							task.logEvent("priority", task.Priority.String()+" → "+priority.String(), now)
+						}
+						task.setURLs(parseURLs(m.taskInputs[2].Value()), now)
+						if newID := m.config.Categories[catIndex].ID; task.CategoryID != newID {
+							task.logEvent("recategorized", m.config.categoryName(task.CategoryID)+" → "+m.config.Categories[catIndex].Name, now)
+						}
+						task.Content = content
+						task.Priority = priority
+						task.CategoryID = m.config.Categories[catIndex].ID
 						break
 					}
 				}
@@ -2191,6 +9264,9 @@ func (m model) handleTaskEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.formFocus < len(m.taskInputs) {
 		m.taskInputs[m.formFocus], cmd = m.taskInputs[m.formFocus].Update(msg)
 	}
+	if strings.TrimSpace(m.taskInputs[0].Value()) != "" {
+		m.contentErr = ""
+	}
 	return m, cmd
 }
 
@@ -2202,10 +9278,11 @@ func (m model) handleTaskDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Save and exit
 			if m.editingTask != nil {
 				notes := strings.TrimSpace(m.notesTextarea.Value())
-				m.editingTask.Notes = notes
+				m.editingTask.setNotes(notes, time.Now())
 				m.saveConfigAndMarkChanged()
 				m.setStatus("Notes saved")
 			}
+			m.flushPriority()
 			m.mode = m.prevMode
 			m.editingTask = nil
 			m.notesTextarea.Blur()
@@ -2213,7 +9290,8 @@ func (m model) handleTaskDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "n", "N":
-			// Discard and exit
+			// Discard and exit; only the notes are thrown away
+			m.flushPriority()
 			m.mode = m.prevMode
 			m.editingTask = nil
 			m.notesTextarea.Blur()
@@ -2225,6 +9303,11 @@ func (m model) handleTaskDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Cancel - continue editing
 			m.showingSaveConfirm = false
 			return m, nil
+
+		case "ctrl+c":
+			// Quitting keeps the notes rather than losing them
+			m.flushNotes()
+			return m.quit()
 		}
 		return m, nil
 	}
@@ -2241,30 +9324,77 @@ func (m model) handleTaskDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		// No changes - exit directly
+		m.flushPriority()
 		m.mode = m.prevMode
 		m.editingTask = nil
 		m.notesTextarea.Blur()
 		return m, nil
 
+	case "alt+=", "alt++", "ctrl+up", "alt+-", "ctrl+down":
+		// Plain +/- would type into the notes, so priority takes alt;
+		// the change is saved on the way out
+		if m.editingTask != nil {
+			next := m.editingTask.Priority - 1
+			if k := msg.String(); k == "alt+-" || k == "ctrl+down" {
+				next = m.editingTask.Priority + 1
+			}
+			if next >= P0Critical && next <= P3Low {
+				m.editingTask.Priority = next
+			}
+			m.setStatus("Priority " + m.editingTask.Priority.String())
+		}
+		return m, nil
+
 	case "ctrl+s":
 		// Manual save with Ctrl+S
 		if m.editingTask != nil {
 			notes := strings.TrimSpace(m.notesTextarea.Value())
-			m.editingTask.Notes = notes
+			m.editingTask.setNotes(notes, time.Now())
 			m.saveConfigAndMarkChanged()
+			m.originalNotes = notes
 			m.setStatus("Notes saved")
 		}
 		return m, nil
 
-	case "ctrl+e":
-		// Edit task - save notes first, then switch to edit mode
+	case "ctrl+y":
+		// Copy task ID for use with CLI commands and scripts
 		if m.editingTask != nil {
-			notes := strings.TrimSpace(m.notesTextarea.Value())
-			if m.editingTask.Notes != notes {
-				m.editingTask.Notes = notes
-				m.saveConfigAndMarkChanged()
+			if err := clipboard.WriteAll(m.editingTask.ID); err != nil {
+				m.setStatus("Copy failed: " + err.Error())
+			} else {
+				m.setStatus("Copied task ID")
 			}
 		}
+		return m, nil
+
+	case "ctrl+o":
+		if m.editingTask != nil {
+			return m.openTaskLinks(*m.editingTask)
+		}
+		return m, nil
+
+	case "ctrl+c":
+		// Quitting from the detail view keeps in-progress notes
+		m.flushNotes()
+		return m.quit()
+
+	case "ctrl+t":
+		// Backdate the task - save notes first, then ask for the date
+		if m.editingTask != nil {
+			m.flushNotes()
+			m.notesTextarea.Blur()
+			m.mode = createdDateView
+			m.dateErr = ""
+			m.dateInput.SetValue(m.editingTask.CreatedAt.Format("2006-01-02"))
+			m.dateInput.CursorEnd()
+			m.dateInput.Focus()
+			return m, textinput.Blink
+		}
+		return m, nil
+
+	case "ctrl+e":
+		// Edit task - save notes first, then switch to edit mode
+		m.flushNotes()
 		m.notesTextarea.Blur()
 
 		// Transition to edit mode
@@ -2277,74 +9407,253 @@ func (m model) handleTaskDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.taskInputs[0].Focus()
 			m.taskInputs[1].SetValue(fmt.Sprintf("%d", m.editingTask.Priority))
 			m.taskInputs[1].Blur()
+			m.taskInputs[2].SetValue(strings.Join(m.editingTask.URLs, " "))
+			m.taskInputs[2].Blur()
 		}
 
 		return m, textinput.Blink
 	}
 
+	before := m.notesTextarea.Value()
+	m.notesTextarea, cmd = m.notesTextarea.Update(msg)
+
+	// Debounce autosave: each edit schedules a save that only the last
+	// one's timer gets to perform
+	if delay := m.config.NotesAutosaveMs; delay > 0 && m.notesTextarea.Value() != before {
+		m.notesEditSeq++
+		m.notesAutosaved = false
+		seq := m.notesEditSeq
+		autosave := tea.Tick(time.Duration(delay)*time.Millisecond, func(time.Time) tea.Msg {
+			return notesAutosaveMsg{seq: seq}
+		})
+		return m, tea.Batch(cmd, autosave)
+	}
+	return m, cmd
+}
+
+// flushNotes writes pending notes from the detail view textarea to the
+// task, or from the category notes view to the category
+func (m *model) flushNotes() {
+	if m.mode == categoryNotesView {
+		m.saveCategoryNotes()
+		return
+	}
+	if m.mode != taskDetailView || m.editingTask == nil {
+		return
+	}
+	notes := strings.TrimSpace(m.notesTextarea.Value())
+	if m.editingTask.setNotes(notes, time.Now()) {
+		m.saveConfigAndMarkChanged()
+	}
+	m.flushPriority()
+}
+
+// flushPriority saves a priority changed in the detail view, logging one
+// event for the whole change however many steps it took
+func (m *model) flushPriority() {
+	if m.editingTask == nil || m.editingTask.Priority == m.originalPriority {
+		return
+	}
+	m.editingTask.logEvent("priority", m.originalPriority.String()+" → "+m.editingTask.Priority.String(), time.Now())
+	m.originalPriority = m.editingTask.Priority
+	m.saveConfigAndMarkChanged()
+	m.updateLists()
+}
+
+// handleCategoryNotes edits the scratchpad of the category picked with n
+// in the category list, with the same keys as task notes
+func (m model) handleCategoryNotes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.showingSaveConfirm {
+		switch msg.String() {
+		case "y", "Y":
+			m.saveCategoryNotes()
+			m.setStatus("Notes saved")
+			return m.leaveCategoryNotes()
+		case "n", "N":
+			m.setStatus("Changes discarded")
+			return m.leaveCategoryNotes()
+		case "esc", "c", "C":
+			m.showingSaveConfirm = false
+		case "ctrl+c":
+			m.saveCategoryNotes()
+			return m.quit()
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		if strings.TrimSpace(m.notesTextarea.Value()) != m.originalNotes {
+			m.showingSaveConfirm = true
+			return m, nil
+		}
+		return m.leaveCategoryNotes()
+
+	case "ctrl+s":
+		m.saveCategoryNotes()
+		m.originalNotes = strings.TrimSpace(m.notesTextarea.Value())
+		m.setStatus("Notes saved")
+		return m, nil
+
+	case "ctrl+c":
+		m.saveCategoryNotes()
+		return m.quit()
+	}
+
+	var cmd tea.Cmd
 	m.notesTextarea, cmd = m.notesTextarea.Update(msg)
 	return m, cmd
 }
 
+// saveCategoryNotes writes the textarea to the category being edited
+func (m *model) saveCategoryNotes() {
+	notes := strings.TrimSpace(m.notesTextarea.Value())
+	for i := range m.config.Categories {
+		if m.config.Categories[i].ID == m.notesCategoryID && m.config.Categories[i].Notes != notes {
+			m.config.Categories[i].Notes = notes
+			m.saveConfigAndMarkChanged()
+			m.updateCategoryList()
+			return
+		}
+	}
+}
+
+// leaveCategoryNotes returns to the category list
+func (m model) leaveCategoryNotes() (tea.Model, tea.Cmd) {
+	m.mode = categoryListView
+	m.notesCategoryID = ""
+	m.showingSaveConfirm = false
+	m.notesTextarea.Blur()
+	return m, nil
+}
+
+func (m model) renderCategoryNotes() string {
+	var output strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(colorAccent)
+	output.WriteString(titleStyle.Render("📝 " + m.config.categoryName(m.notesCategoryID) + " notes"))
+	output.WriteString("\n\n")
+	output.WriteString(m.notesTextarea.View())
+	output.WriteString("\n\n")
+
+	if time.Now().Before(m.statusUntil) {
+		output.WriteString(lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Render("✓ " + m.statusMsg))
+		output.WriteString("  ")
+	}
+	output.WriteString(lipgloss.NewStyle().Foreground(colorMuted).Render("ctrl+s: save notes | esc: save and return"))
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
+}
+
 func (m model) renderEditTaskForm() string {
 	var output strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#4ec9b0"))
+		Foreground(colorAccent)
 
 	output.WriteString(titleStyle.Render("Edit Task"))
 	output.WriteString("\n\n")
 
 	// Task content input
-	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#999"))
+	labelStyle := lipgloss.NewStyle().Foreground(colorSubtle)
 	if m.formFocus == 0 {
-		labelStyle = labelStyle.Foreground(lipgloss.Color("#4ec9b0"))
+		labelStyle = labelStyle.Foreground(colorAccent)
 	}
 	output.WriteString(labelStyle.Render("Content:"))
 	output.WriteString("\n")
 	output.WriteString(m.taskInputs[0].View())
-	output.WriteString("\n\n")
+	output.WriteString("\n")
+	if m.contentErr != "" {
+		errStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#d73a4a"))
+		output.WriteString(errStyle.Render(m.contentErr))
+	}
+	output.WriteString("\n")
 
 	// Priority input
-	labelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#999"))
+	labelStyle = lipgloss.NewStyle().Foreground(colorSubtle)
 	if m.formFocus == 1 {
-		labelStyle = labelStyle.Foreground(lipgloss.Color("#4ec9b0"))
+		labelStyle = labelStyle.Foreground(colorAccent)
 	}
 	output.WriteString(labelStyle.Render("Priority (0-3):"))
 	output.WriteString("\n")
 	output.WriteString(m.taskInputs[1].View())
 	output.WriteString("\n\n")
 
-	// Category selection
-	output.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#999")).Render("Category:"))
+	// URL input
+	labelStyle = lipgloss.NewStyle().Foreground(colorSubtle)
+	if m.formFocus == 2 {
+		labelStyle = labelStyle.Foreground(colorAccent)
+	}
+	output.WriteString(labelStyle.Render("URLs (separate with spaces):"))
 	output.WriteString("\n")
+	output.WriteString(m.taskInputs[2].View())
+	output.WriteString("\n\n")
 
-	for i, cat := range m.config.Categories {
-		catIndex := len(m.taskInputs) + i
-		cursor := "  "
-		style := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
-
-		// Highlight current category
-		if m.editingTask != nil && cat.ID == m.editingTask.CategoryID && m.formFocus != catIndex {
-			cursor = "* "
+	// Fields this form doesn't edit, shown so it's clear they survive a save
+	if kept := m.keptTaskFields(); len(kept) > 0 {
+		output.WriteString(lipgloss.NewStyle().Foreground(colorSubtle).Render("Kept as is (notes are edited in task details):"))
+		output.WriteString("\n")
+		keptStyle := lipgloss.NewStyle().Foreground(colorMuted)
+		for _, line := range kept {
+			output.WriteString(keptStyle.Render("  " + line))
+			output.WriteString("\n")
 		}
+		output.WriteString("\n")
+	}
 
-		if m.formFocus == catIndex {
-			cursor = "> "
-			style = style.Foreground(lipgloss.Color("#4ec9b0")).Bold(true)
-		}
+	// Category selection
+	output.WriteString(lipgloss.NewStyle().Foreground(colorSubtle).Render("Category:"))
+	output.WriteString("\n")
 
-		output.WriteString(cursor + style.Render(cat.Name) + "\n")
+	currentID := ""
+	if m.editingTask != nil {
+		currentID = m.editingTask.CategoryID
 	}
+	output.WriteString(m.renderCategoryPicker(currentID))
 
 	output.WriteString("\n")
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
 	output.WriteString(helpStyle.Render("arrows: navigate | enter: next/save | esc: cancel"))
 
 	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
 }
 
+// keptTaskFields summarizes the editing task's fields the edit form leaves
+// alone, one line each
+func (m model) keptTaskFields() []string {
+	if m.mode != editTaskView || m.editingTask == nil {
+		return nil
+	}
+	task := m.editingTask
+	width := max(m.width-12, 20)
+
+	var lines []string
+	if task.Notes != "" {
+		notes := strings.Split(task.Notes, "\n")
+		line := "Notes: " + notes[0]
+		if len(notes) > 1 {
+			line += fmt.Sprintf(" (+%d lines)", len(notes)-1)
+		}
+		lines = append(lines, ansi.Truncate(line, width, "…"))
+	}
+	if state := task.state(); state != StateTodo && state != StateDone {
+		lines = append(lines, "State: "+string(state))
+	}
+	if task.isSnoozed(time.Now()) {
+		lines = append(lines, "💤 "+m.config.timeStyle().snoozeText(task.SnoozedUntil, time.Now()))
+	}
+	if spent := task.SpentMinutes; spent > 0 {
+		lines = append(lines, fmt.Sprintf("Focused: %dh %02dm", spent/60, spent%60))
+	}
+	if n := len(task.Events); n == 1 {
+		lines = append(lines, "History: 1 event")
+	} else if n > 1 {
+		lines = append(lines, fmt.Sprintf("History: %d events", n))
+	}
+	return lines
+}
+
 func (m model) renderTaskDetailView() string {
 	if m.editingTask == nil {
 		return "No task selected"
@@ -2364,7 +9673,7 @@ func (m model) renderTaskDetailView() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#4ec9b0"))
+		Foreground(colorAccent)
 
 	output.WriteString(titleStyle.Render("Task Details"))
 	output.WriteString("\n\n")
@@ -2372,17 +9681,17 @@ func (m model) renderTaskDetailView() string {
 	// Create a bordered box for task info
 	infoStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#4ec9b0")).
+		BorderForeground(colorAccent).
 		Padding(1, 2).
 		Width(60)
 
 	var info strings.Builder
 	labelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#999")).
+		Foreground(colorSubtle).
 		Bold(true)
 
 	valueStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#d4d4d4"))
+		Foreground(colorText)
 
 	priorityStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(m.editingTask.Priority.Color())).
@@ -2400,59 +9709,124 @@ func (m model) renderTaskDetailView() string {
 	info.WriteString(priorityStyle.Render(m.editingTask.Priority.String()))
 	info.WriteString("\n\n")
 
+	if urls := m.editingTask.URLs; len(urls) > 0 {
+		info.WriteString(labelStyle.Render("Links:"))
+		for i, url := range urls {
+			info.WriteString(fmt.Sprintf("\n%d. ", i+1))
+			if m.config.hyperlinks() {
+				info.WriteString(valueStyle.Underline(true).Render(hyperlink(url, url)))
+			} else {
+				info.WriteString(valueStyle.Render(url))
+			}
+		}
+		info.WriteString("\n\n")
+	}
+
+	times, now := m.config.timeStyle(), time.Now()
 	info.WriteString(labelStyle.Render("Created: "))
-	info.WriteString(valueStyle.Render(m.editingTask.CreatedAt.Format("2006-01-02 15:04")))
+	info.WriteString(valueStyle.Render(times.formatTime(m.editingTask.CreatedAt, now)))
 	info.WriteString("\n\n")
 
-	age := time.Since(m.editingTask.CreatedAt)
-	days := int(age.Hours() / 24)
-	var ageStr string
-	if days == 0 {
-		ageStr = "Created today"
-	} else if days == 1 {
-		ageStr = "1 day old"
-	} else {
-		ageStr = fmt.Sprintf("%d days old", days)
+	// With relative_dates set either way, Created already says it all
+	if m.config.RelativeDates == nil {
+		info.WriteString(labelStyle.Render("Age: "))
+		info.WriteString(valueStyle.Render(times.humanizeAge(m.editingTask.CreatedAt, now)))
+		info.WriteString("\n\n")
+	}
+
+	if spent := m.editingTask.SpentMinutes; spent > 0 {
+		info.WriteString(labelStyle.Render("Focused: "))
+		info.WriteString(valueStyle.Render(fmt.Sprintf("%dh %02dm", spent/60, spent%60)))
+		info.WriteString("\n\n")
 	}
-	info.WriteString(labelStyle.Render("Age: "))
-	info.WriteString(valueStyle.Render(ageStr))
-	info.WriteString("\n\n")
 
 	info.WriteString(labelStyle.Render("Status: "))
 	if m.editingTask.Done {
 		doneStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#4caf50"))
 		info.WriteString(doneStyle.Render("Completed"))
 		if !m.editingTask.CompletedAt.IsZero() {
-			info.WriteString(valueStyle.Render(fmt.Sprintf(" (%s)", m.editingTask.CompletedAt.Format("2006-01-02 15:04"))))
+			info.WriteString(valueStyle.Render(fmt.Sprintf(" (%s)", times.formatTime(m.editingTask.CompletedAt, now))))
+		}
+		if d, ok := m.editingTask.cycleTime(); ok {
+			info.WriteString(valueStyle.Render(" • " + formatCycleTime(d)))
+		}
+		if n := m.editingTask.TimesCompleted; n > 1 {
+			info.WriteString(valueStyle.Render(fmt.Sprintf(" • completed %d times", n)))
 		}
 	} else {
-		pendingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#ffc107"))
-		info.WriteString(pendingStyle.Render("Pending"))
+		pendingStyle := lipgloss.NewStyle().Foreground(colorWarning)
+		switch m.editingTask.state() {
+		case StateDoing:
+			info.WriteString(pendingStyle.Render("In progress"))
+		case StateWaiting:
+			info.WriteString(pendingStyle.Render("Waiting"))
+		default:
+			info.WriteString(pendingStyle.Render("Pending"))
+		}
+		switch n := m.editingTask.TimesCompleted; {
+		case n == 1:
+			info.WriteString(valueStyle.Render(" • reopened after 1 completion"))
+		case n > 1:
+			info.WriteString(valueStyle.Render(fmt.Sprintf(" • reopened after %d completions", n)))
+		}
 	}
+	info.WriteString("\n\n")
+
+	idStyle := lipgloss.NewStyle().
+		Foreground(colorMuted).
+		Faint(true)
+	info.WriteString(labelStyle.Render("ID: "))
+	info.WriteString(idStyle.Render(m.editingTask.ID))
+	info.WriteString(idStyle.Render(fmt.Sprintf("  (category: %s)", m.editingTask.CategoryID)))
 
 	output.WriteString(infoStyle.Render(info.String()))
 	output.WriteString("\n\n")
 
+	// History timeline, most recent last
+	if events := m.editingTask.Events; len(events) > 0 {
+		const shown = 5
+		historyStyle := lipgloss.NewStyle().Foreground(colorSubtle)
+		output.WriteString(lipgloss.NewStyle().Foreground(colorAccent).Bold(true).Render("History:"))
+		if len(events) > shown {
+			output.WriteString(historyStyle.Render(fmt.Sprintf(" (%d earlier)", len(events)-shown)))
+			events = events[len(events)-shown:]
+		}
+		output.WriteString("\n")
+		for _, e := range events {
+			line := fmt.Sprintf("%-16s  %-13s %s", times.formatTime(e.At, now), e.Type, e.Detail)
+			output.WriteString(historyStyle.Render(ansi.Truncate(line, 76, "…")))
+			output.WriteString("\n")
+		}
+		output.WriteString("\n")
+	}
+
 	// Notes section
 	notesLabelStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#4ec9b0")).
+		Foreground(colorAccent).
 		Bold(true)
 
 	output.WriteString(notesLabelStyle.Render("Notes:"))
+	if m.notesAutosaved {
+		output.WriteString(lipgloss.NewStyle().Foreground(colorFaint).Render("  saved"))
+	}
 	output.WriteString("\n")
 	output.WriteString(m.notesTextarea.View())
 	output.WriteString("\n\n")
 
 	// Status message (if active)
-	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#4ec9b0")).Bold(true)
-	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#666"))
+	statusStyle := lipgloss.NewStyle().Foreground(colorAccent).Bold(true)
+	helpStyle := lipgloss.NewStyle().Foreground(colorMuted)
 
 	if time.Now().Before(m.statusUntil) {
 		output.WriteString(statusStyle.Render("✓ " + m.statusMsg))
 		output.WriteString("  ")
 	}
 
-	output.WriteString(helpStyle.Render("ctrl+e: edit task | ctrl+s: save notes | esc: save and return"))
+	help := "ctrl+e: edit task | ctrl+o: open link | alt+=/alt+-: priority | ctrl+t: created date | ctrl+s: save notes | ctrl+y: copy ID | esc: save and return"
+	if m.readonly {
+		help = "ctrl+y: copy ID | ctrl+o: open link | esc: return"
+	}
+	output.WriteString(helpStyle.Render(help))
 
 	return lipgloss.NewStyle().Padding(1, 2).Render(output.String())
 }
@@ -2471,7 +9845,7 @@ func (m model) handleFirstRun(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// User has existing repo, start pulling
 			m.firstRunStep = pullingStep
 			m.pullInProgress = true
-			return m, tea.Batch(pullFromGitHubCmd(m.config), m.spinner.Tick)
+			return m, m.startPull()
 		case "n", "N":
 			// User doesn't have repo, ask if they want to create one
 			m.firstRunStep = createRepoPromptStep
@@ -2480,7 +9854,7 @@ func (m model) handleFirstRun(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Skip GitHub setup for now
 			m.config.GitHubSetupComplete = true
 			m.saveConfigAndMarkChanged()
-			m.mode = listView
+			m.mode = m.postSetupView()
 			m.updateLists()
 			m.setStatus("GitHub sync skipped - you can sync later with 'G' or 'g'")
 			return m, nil
@@ -2489,15 +9863,16 @@ func (m model) handleFirstRun(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case createRepoPromptStep:
 		switch msg.String() {
 		case "y", "Y":
-			// Create new repo by pushing current config
+			// Look for an existing repo first; the push only starts if
+			// there's nothing there to overwrite
 			m.firstRunStep = pushingStep
-			m.syncInProgress = true
-			return m, tea.Batch(syncToGitHubCmd(), m.spinner.Tick)
+			m.pullInProgress = true
+			return m, m.startPull()
 		case "n", "N":
 			// Skip GitHub setup
 			m.config.GitHubSetupComplete = true
 			m.saveConfigAndMarkChanged()
-			m.mode = listView
+			m.mode = m.postSetupView()
 			m.updateLists()
 			m.setStatus("GitHub sync skipped - you can sync later with 'G' or 'g'")
 			return m, nil
@@ -2505,18 +9880,31 @@ func (m model) handleFirstRun(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Skip GitHub setup
 			m.config.GitHubSetupComplete = true
 			m.saveConfigAndMarkChanged()
-			m.mode = listView
+			m.mode = m.postSetupView()
 			m.updateLists()
 			m.setStatus("GitHub sync skipped - you can sync later with 'G' or 'g'")
 			return m, nil
 		}
 
 	case pullingStep, pushingStep:
+		// ctrl+c/esc abort a slow or hung sync and fall back to local tasks
+		if m.firstRunError == "" && (msg.String() == "ctrl+c" || msg.String() == "esc") {
+			m.cancelSync()
+			m.syncInProgress = false
+			m.pullInProgress = false
+			m.config.GitHubSetupComplete = true
+			m.saveConfigAndMarkChanged()
+			m.mode = m.postSetupView()
+			m.updateLists()
+			m.setStatus("Sync cancelled - continuing with local tasks")
+			return m, nil
+		}
+
 		// If there's an error, allow any key to continue with local tasks
 		if m.firstRunError != "" {
 			m.config.GitHubSetupComplete = true
 			m.saveConfigAndMarkChanged()
-			m.mode = listView
+			m.mode = m.postSetupView()
 			m.updateLists()
 			m.setStatus("Continuing with local tasks - sync later with 'G' or 'g'")
 			return m, nil
@@ -2526,7 +9914,7 @@ func (m model) handleFirstRun(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Any key transitions to main view
 		m.config.GitHubSetupComplete = true
 		m.saveConfigAndMarkChanged()
-		m.mode = listView
+		m.mode = m.postSetupView()
 		m.updateLists()
 		return m, nil
 	}
@@ -2534,24 +9922,109 @@ func (m model) handleFirstRun(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// postSetupView is where first-run setup hands off: the onboarding tour
+// the first time, the task list after that
+func (m model) postSetupView() viewMode {
+	if m.config.TourComplete {
+		return listView
+	}
+	return tourView
+}
+
+// handleTour steps through the onboarding tour; any exit marks it seen
+func (m model) handleTour(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", " ", "right", "l", "n":
+		if m.tourStep < len(tourSteps)-1 {
+			m.tourStep++
+			return m, nil
+		}
+		return m.endTour("Tour complete - press ? for all key bindings")
+	case "left", "h", "p":
+		if m.tourStep > 0 {
+			m.tourStep--
+		}
+		return m, nil
+	case "esc", "s", "q", "ctrl+c":
+		return m.endTour("Tour skipped - press ? for all key bindings")
+	}
+	return m, nil
+}
+
+// endTour records the tour as seen so it never shows again
+func (m model) endTour(status string) (tea.Model, tea.Cmd) {
+	m.config.TourComplete = true
+	m.saveConfigAndMarkChanged()
+	m.mode = listView
+	m.updateLists()
+	m.setStatus(status)
+	return m, nil
+}
+
+// renderTour draws the current tour step as a centered overlay
+func (m model) renderTour() string {
+	step := tourSteps[m.tourStep]
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent)
+
+	keyStyle := lipgloss.NewStyle().
+		Foreground(colorAccent).
+		Bold(true)
+
+	infoStyle := lipgloss.NewStyle().
+		Foreground(colorText).
+		Width(50)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(colorMuted)
+
+	next := "enter: next"
+	if m.tourStep == len(tourSteps)-1 {
+		next = "enter: finish"
+	}
+	help := next + " | esc: skip tour"
+	if m.tourStep > 0 {
+		help = "←: back | " + help
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(fmt.Sprintf("Quick tour %d/%d: %s", m.tourStep+1, len(tourSteps), step.title)),
+		"",
+		keyStyle.Render(step.keys),
+		infoStyle.Render(step.text),
+		"",
+		helpStyle.Render(help),
+	)
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorAccent).
+		Padding(1, 2).
+		Render(content)
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, dialog)
+}
+
 // renderFirstRun displays the first-run setup UI
 func (m model) renderFirstRun() string {
 	var output strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#4ec9b0")).
+		Foreground(colorAccent).
 		Align(lipgloss.Center)
 
 	infoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#d4d4d4"))
+		Foreground(colorText)
 
 	highlightStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#4ec9b0")).
+		Foreground(colorAccent).
 		Bold(true)
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#666"))
+		Foreground(colorMuted)
 
 	errorStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#d73a4a")).
@@ -2563,14 +10036,14 @@ func (m model) renderFirstRun() string {
 		output.WriteString("\n\n")
 		output.WriteString(infoStyle.Render("todobi syncs your tasks across machines using GitHub."))
 		output.WriteString("\n")
-		output.WriteString(infoStyle.Render("Your tasks are stored in a private repo called 'todobi-sync'."))
+		output.WriteString(infoStyle.Render(fmt.Sprintf("Your tasks are stored in the GitHub repo '%s' (%s).", m.config.syncRepoPath(), m.config.syncVisibility())))
 		output.WriteString("\n\n")
 		output.WriteString(helpStyle.Render("Press any key to continue..."))
 
 	case hasRepoPromptStep:
 		output.WriteString(titleStyle.Render("GitHub Setup"))
 		output.WriteString("\n\n")
-		output.WriteString(infoStyle.Render("Do you have an existing todobi-sync repo on GitHub?"))
+		output.WriteString(infoStyle.Render(fmt.Sprintf("Do you have an existing %s repo on GitHub?", m.config.syncRepoPath())))
 		output.WriteString("\n\n")
 		output.WriteString(highlightStyle.Render("Y: "))
 		output.WriteString(infoStyle.Render("Yes, pull my tasks from GitHub"))
@@ -2583,9 +10056,9 @@ func (m model) renderFirstRun() string {
 	case createRepoPromptStep:
 		output.WriteString(titleStyle.Render("Create GitHub Repo"))
 		output.WriteString("\n\n")
-		output.WriteString(infoStyle.Render("Would you like to create a todobi-sync repo now?"))
+		output.WriteString(infoStyle.Render(fmt.Sprintf("Would you like to create a %s repo now?", m.config.syncRepoPath())))
 		output.WriteString("\n")
-		output.WriteString(infoStyle.Render("This will create a private GitHub repo and sync your tasks."))
+		output.WriteString(infoStyle.Render(fmt.Sprintf("This will create it with %s visibility and sync your tasks.", m.config.syncVisibility())))
 		output.WriteString("\n\n")
 		output.WriteString(highlightStyle.Render("Y: "))
 		output.WriteString(infoStyle.Render("Yes, create repo and sync"))
@@ -2599,7 +10072,10 @@ func (m model) renderFirstRun() string {
 		output.WriteString(titleStyle.Render("Pulling from GitHub"))
 		output.WriteString("\n\n")
 		output.WriteString(fmt.Sprintf("%s %s", m.spinner.View(), infoStyle.Render("Pulling your tasks from GitHub...")))
-		if m.firstRunError != "" {
+		if m.firstRunError == "" {
+			output.WriteString("\n\n")
+			output.WriteString(helpStyle.Render("ctrl+c/esc: cancel and continue with local tasks"))
+		} else {
 			output.WriteString("\n\n")
 			output.WriteString(errorStyle.Render("Error: " + m.firstRunError))
 			output.WriteString("\n\n")
@@ -2609,8 +10085,15 @@ func (m model) renderFirstRun() string {
 	case pushingStep:
 		output.WriteString(titleStyle.Render("Creating GitHub Repo"))
 		output.WriteString("\n\n")
-		output.WriteString(fmt.Sprintf("%s %s", m.spinner.View(), infoStyle.Render("Creating private repo on GitHub...")))
-		if m.firstRunError != "" {
+		progress := fmt.Sprintf("Creating %s (%s) on GitHub...", m.config.syncRepoPath(), m.config.syncVisibility())
+		if m.pullInProgress {
+			progress = "Checking for an existing repo..."
+		}
+		output.WriteString(fmt.Sprintf("%s %s", m.spinner.View(), infoStyle.Render(progress)))
+		if m.firstRunError == "" {
+			output.WriteString("\n\n")
+			output.WriteString(helpStyle.Render("ctrl+c/esc: cancel and continue with local tasks"))
+		} else {
 			output.WriteString("\n\n")
 			output.WriteString(errorStyle.Render("Error: " + m.firstRunError))
 			output.WriteString("\n\n")